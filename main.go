@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/rancher/hull/pkg/chart"
+	"github.com/rancher/hull/pkg/test"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	Version   = "v0.0.0-dev"
+	GitCommit = "HEAD"
+)
+
+type testOptions struct {
+	Suite           string
+	Run             string
+	Verbose         bool
+	Rancher         bool
+	YAMLLint        bool
+	DisableCoverage bool
+	Snapshot        bool
+	SnapshotDir     string
+	Update          bool
+	PreviousChart   string
+	ValidateSchema  bool
+	KubeSchema      string
+	CRDs            []string
+	CRDCharts       []string
+	KubeVersions    []string
+	AllKubeVersions bool
+
+	DeprecatedAPIs       bool
+	FailOnDeprecatedAPIs bool
+}
+
+func main() {
+	root := &cobra.Command{
+		Use:           "hull",
+		Short:         "Hull is a testing framework for Helm charts",
+		Version:       fmt.Sprintf("%s (%s)", Version, GitCommit),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.AddCommand(newTestCommand())
+	root.AddCommand(newGenerateCommand())
+	if err := root.Execute(); err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+func newTestCommand() *cobra.Command {
+	opts := &testOptions{}
+	cmd := &cobra.Command{
+		Use:   "test [chart-dir]",
+		Short: "Run a declarative test suite against a Helm chart",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			var chartPath string
+			if len(args) > 0 {
+				chartPath = args[0]
+			}
+			return runTest(chartPath, opts)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.Suite, "suite", "s", "hull-suite.yaml", "Path to the suite file to run")
+	cmd.Flags().StringVar(&opts.Run, "run", "", "Only run cases and checks whose test name matches this regular expression")
+	cmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false, "Log all tests as they are run")
+	cmd.Flags().BoolVar(&opts.Rancher, "rancher", false, "Run additional lint checks for Rancher charts")
+	cmd.Flags().BoolVar(&opts.YAMLLint, "yamllint", false, "Run yamllint on rendered manifests (requires yamllint)")
+	cmd.Flags().BoolVar(&opts.DisableCoverage, "disable-coverage", false, "Do not require the suite to cover every .Values reference")
+	cmd.Flags().BoolVar(&opts.Snapshot, "snapshot", false, "Compare the rendered templates of each case against committed snapshots")
+	cmd.Flags().StringVar(&opts.SnapshotDir, "snapshot-dir", "", "Directory containing snapshots (default: testdata/__snapshots__)")
+	cmd.Flags().BoolVar(&opts.Update, "update", false, "Update snapshots instead of comparing against them")
+	cmd.Flags().BoolVar(&opts.ValidateSchema, "validate-schema", false, "Validate rendered objects against the OpenAPI schemas for each case's kubeVersion")
+	cmd.Flags().StringVar(&opts.KubeSchema, "kube-schema", "", "Path to an OpenAPI v2 spec to validate built-in resources against instead of the bundled schemas")
+	cmd.Flags().StringSliceVar(&opts.CRDs, "crds", nil, "Paths to files or directories containing CRDs to validate custom resources against")
+	cmd.Flags().StringSliceVar(&opts.CRDCharts, "crd-charts", nil, "Paths to charts that install CRDs used by the chart (i.e. a companion CRD chart)")
+	cmd.Flags().StringSliceVar(&opts.KubeVersions, "kube-versions", nil, "Run every case against each of these versions of Kubernetes")
+	cmd.Flags().BoolVar(&opts.AllKubeVersions, "all-kube-versions", false, "Run every case against each version of Kubernetes allowed by the chart's kubeVersion or catalog.cattle.io/kube-version annotation")
+	cmd.Flags().BoolVar(&opts.DeprecatedAPIs, "deprecated-apis", false, "Check that rendered objects use APIs served by each case's kubeVersion")
+	cmd.Flags().BoolVar(&opts.FailOnDeprecatedAPIs, "fail-on-deprecated-apis", false, "Check that rendered objects use APIs served by each case's kubeVersion and fail instead of warning on deprecated APIs that are still served")
+	cmd.Flags().StringVar(&opts.PreviousChart, "previous-chart", "", "Path to a previous version of the chart to check that each case can be upgraded from")
+	return cmd
+}
+
+type generateOptions struct {
+	Suite            string
+	Format           string
+	IncludeSubcharts bool
+}
+
+func newGenerateCommand() *cobra.Command {
+	opts := &generateOptions{}
+	cmd := &cobra.Command{
+		Use:   "generate [chart-dir]",
+		Short: "Print cases that set each .Values field not yet covered by a test suite",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			var chartPath string
+			if len(args) > 0 {
+				chartPath = args[0]
+			}
+			return runGenerate(chartPath, opts)
+		},
+	}
+	cmd.Flags().StringVarP(&opts.Suite, "suite", "s", "hull-suite.yaml", "Path to the suite file whose coverage should be completed (ignored if it does not exist)")
+	cmd.Flags().StringVarP(&opts.Format, "format", "o", "yaml", "Output format of the generated cases: yaml or go")
+	cmd.Flags().BoolVar(&opts.IncludeSubcharts, "include-subcharts", false, "Generate cases for fields only referenced by subcharts")
+	return cmd
+}
+
+func runGenerate(chartPath string, opts *generateOptions) error {
+	suite := &test.Suite{}
+	if _, err := os.Stat(opts.Suite); err == nil {
+		suite, err = test.LoadSuite(opts.Suite)
+		if err != nil {
+			return err
+		}
+	}
+	if len(chartPath) > 0 {
+		suite.ChartPath = chartPath
+	}
+	if len(suite.ChartPath) == 0 {
+		return fmt.Errorf("no chart provided: either pass a chart directory or set chartPath in %s", opts.Suite)
+	}
+	fields, err := suite.UncoveredFields(opts.IncludeSubcharts)
+	if err != nil {
+		return err
+	}
+	c, err := chart.NewChart(suite.ChartPath)
+	if err != nil {
+		return err
+	}
+	cases, err := test.GenerateCases(c, fields)
+	if err != nil {
+		return err
+	}
+	var out []byte
+	switch opts.Format {
+	case "yaml":
+		out, err = test.FormatCasesAsSuite(cases)
+	case "go":
+		out, err = test.FormatCasesAsGo(cases)
+	default:
+		return fmt.Errorf("unknown format %s: must be one of yaml or go", opts.Format)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(out)
+	return err
+}
+
+func runTest(chartPath string, opts *testOptions) error {
+	suite, err := test.LoadSuite(opts.Suite)
+	if err != nil {
+		return err
+	}
+	if len(chartPath) > 0 {
+		suite.ChartPath = chartPath
+	}
+	if len(suite.ChartPath) == 0 {
+		return fmt.Errorf("no chart provided: either pass a chart directory or set chartPath in %s", opts.Suite)
+	}
+	suiteOpts := &test.SuiteOptions{}
+	if opts.Rancher {
+		suiteOpts = test.GetRancherOptions()
+	}
+	suiteOpts.YAMLLint.Enabled = opts.YAMLLint
+	suiteOpts.Coverage.Disabled = opts.DisableCoverage
+	suiteOpts.Snapshot = test.SnapshotOptions{
+		Enabled:   opts.Snapshot,
+		Directory: opts.SnapshotDir,
+		Update:    opts.Update,
+	}
+	suiteOpts.SchemaValidation = test.SchemaValidationOptions{
+		Enabled:              opts.ValidateSchema,
+		KubernetesSchemaPath: opts.KubeSchema,
+		CRDChartPaths:        opts.CRDCharts,
+		CRDPaths:             opts.CRDs,
+	}
+	suiteOpts.KubeVersions = test.KubeVersionOptions{
+		Enabled:  opts.AllKubeVersions || len(opts.KubeVersions) > 0,
+		Versions: opts.KubeVersions,
+	}
+	suiteOpts.DeprecatedAPIs = test.DeprecatedAPIOptions{
+		Enabled:          opts.DeprecatedAPIs || opts.FailOnDeprecatedAPIs,
+		FailOnDeprecated: opts.FailOnDeprecatedAPIs,
+	}
+	suiteOpts.Upgrade = test.UpgradeOptions{
+		Enabled:           len(opts.PreviousChart) > 0,
+		PreviousChartPath: opts.PreviousChart,
+	}
+
+	// testing.Main parses the standard go test flags from os.Args
+	os.Args = []string{os.Args[0], fmt.Sprintf("-test.v=%t", opts.Verbose)}
+	if len(opts.Run) > 0 {
+		os.Args = append(os.Args, "-test.run=^Hull$/"+opts.Run)
+	}
+	testing.Main(matchString, []testing.InternalTest{
+		{
+			Name: "Hull",
+			F: func(t *testing.T) {
+				suite.Run(t, suiteOpts)
+			},
+		},
+	}, nil, nil)
+	return nil
+}
+
+func matchString(pat, str string) (bool, error) {
+	return regexp.MatchString(pat, str)
+}
@@ -0,0 +1,160 @@
+package chart
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+	helmChartUtil "helm.sh/helm/v3/pkg/chartutil"
+)
+
+const (
+	minSupportedKubeMinor = 16
+	maxSupportedKubeMinor = 32
+)
+
+// apiResource is a kind served by a group version of the Kubernetes API between two minor versions of Kubernetes
+type apiResource struct {
+	groupVersion string
+	kinds        []string
+
+	// introduced is the first minor version that serves the kinds or empty if they were served before the oldest supported version
+	introduced string
+	// deprecated is the first minor version that marks the kinds as deprecated or empty if they are not deprecated
+	deprecated string
+	// removed is the first minor version that no longer serves the kinds or empty if they are still served
+	removed string
+	// replacement describes the API that should be used instead of a deprecated API
+	replacement string
+}
+
+// apiResources lists the built-in kinds served by default by each supported version of Kubernetes, including those that have since been removed
+var apiResources = []apiResource{
+	{groupVersion: "v1", kinds: []string{"Binding", "ComponentStatus", "ConfigMap", "Endpoints", "Event", "LimitRange", "Namespace", "Node", "PersistentVolume", "PersistentVolumeClaim", "Pod", "PodTemplate", "ReplicationController", "ResourceQuota", "Secret", "Service", "ServiceAccount"}},
+
+	{groupVersion: "admissionregistration.k8s.io/v1", kinds: []string{"MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"}},
+	{groupVersion: "admissionregistration.k8s.io/v1", kinds: []string{"ValidatingAdmissionPolicy", "ValidatingAdmissionPolicyBinding"}, introduced: "1.30"},
+	{groupVersion: "admissionregistration.k8s.io/v1beta1", kinds: []string{"MutatingWebhookConfiguration", "ValidatingWebhookConfiguration"}, deprecated: "1.16", removed: "1.22", replacement: "admissionregistration.k8s.io/v1"},
+
+	{groupVersion: "apiextensions.k8s.io/v1", kinds: []string{"CustomResourceDefinition"}},
+	{groupVersion: "apiextensions.k8s.io/v1beta1", kinds: []string{"CustomResourceDefinition"}, deprecated: "1.16", removed: "1.22", replacement: "apiextensions.k8s.io/v1"},
+
+	{groupVersion: "apiregistration.k8s.io/v1", kinds: []string{"APIService"}},
+	{groupVersion: "apiregistration.k8s.io/v1beta1", kinds: []string{"APIService"}, deprecated: "1.19", removed: "1.22", replacement: "apiregistration.k8s.io/v1"},
+
+	{groupVersion: "apps/v1", kinds: []string{"ControllerRevision", "DaemonSet", "Deployment", "ReplicaSet", "StatefulSet"}},
+	{groupVersion: "apps/v1beta1", kinds: []string{"ControllerRevision", "Deployment", "StatefulSet"}, deprecated: "1.9", removed: "1.16", replacement: "apps/v1"},
+	{groupVersion: "apps/v1beta2", kinds: []string{"ControllerRevision", "DaemonSet", "Deployment", "ReplicaSet", "StatefulSet"}, deprecated: "1.9", removed: "1.16", replacement: "apps/v1"},
+
+	{groupVersion: "authentication.k8s.io/v1", kinds: []string{"TokenReview"}},
+	{groupVersion: "authentication.k8s.io/v1", kinds: []string{"SelfSubjectReview"}, introduced: "1.28"},
+	{groupVersion: "authentication.k8s.io/v1beta1", kinds: []string{"TokenReview"}, deprecated: "1.19", removed: "1.22", replacement: "authentication.k8s.io/v1"},
+
+	{groupVersion: "authorization.k8s.io/v1", kinds: []string{"LocalSubjectAccessReview", "SelfSubjectAccessReview", "SelfSubjectRulesReview", "SubjectAccessReview"}},
+	{groupVersion: "authorization.k8s.io/v1beta1", kinds: []string{"LocalSubjectAccessReview", "SelfSubjectAccessReview", "SelfSubjectRulesReview", "SubjectAccessReview"}, deprecated: "1.19", removed: "1.22", replacement: "authorization.k8s.io/v1"},
+
+	{groupVersion: "autoscaling/v1", kinds: []string{"HorizontalPodAutoscaler"}},
+	{groupVersion: "autoscaling/v2", kinds: []string{"HorizontalPodAutoscaler"}, introduced: "1.23"},
+	{groupVersion: "autoscaling/v2beta1", kinds: []string{"HorizontalPodAutoscaler"}, deprecated: "1.22", removed: "1.25", replacement: "autoscaling/v2"},
+	{groupVersion: "autoscaling/v2beta2", kinds: []string{"HorizontalPodAutoscaler"}, deprecated: "1.23", removed: "1.26", replacement: "autoscaling/v2"},
+
+	{groupVersion: "batch/v1", kinds: []string{"Job"}},
+	{groupVersion: "batch/v1", kinds: []string{"CronJob"}, introduced: "1.21"},
+	{groupVersion: "batch/v1beta1", kinds: []string{"CronJob"}, deprecated: "1.21", removed: "1.25", replacement: "batch/v1"},
+
+	{groupVersion: "certificates.k8s.io/v1", kinds: []string{"CertificateSigningRequest"}, introduced: "1.19"},
+	{groupVersion: "certificates.k8s.io/v1beta1", kinds: []string{"CertificateSigningRequest"}, deprecated: "1.19", removed: "1.22", replacement: "certificates.k8s.io/v1"},
+
+	{groupVersion: "coordination.k8s.io/v1", kinds: []string{"Lease"}},
+	{groupVersion: "coordination.k8s.io/v1beta1", kinds: []string{"Lease"}, deprecated: "1.19", removed: "1.22", replacement: "coordination.k8s.io/v1"},
+
+	{groupVersion: "discovery.k8s.io/v1", kinds: []string{"EndpointSlice"}, introduced: "1.21"},
+	{groupVersion: "discovery.k8s.io/v1beta1", kinds: []string{"EndpointSlice"}, introduced: "1.17", deprecated: "1.21", removed: "1.25", replacement: "discovery.k8s.io/v1"},
+
+	{groupVersion: "events.k8s.io/v1", kinds: []string{"Event"}, introduced: "1.19"},
+	{groupVersion: "events.k8s.io/v1beta1", kinds: []string{"Event"}, deprecated: "1.19", removed: "1.25", replacement: "events.k8s.io/v1"},
+
+	{groupVersion: "extensions/v1beta1", kinds: []string{"DaemonSet", "Deployment", "ReplicaSet"}, deprecated: "1.9", removed: "1.16", replacement: "apps/v1"},
+	{groupVersion: "extensions/v1beta1", kinds: []string{"NetworkPolicy"}, deprecated: "1.9", removed: "1.16", replacement: "networking.k8s.io/v1"},
+	{groupVersion: "extensions/v1beta1", kinds: []string{"PodSecurityPolicy"}, deprecated: "1.10", removed: "1.16", replacement: "policy/v1beta1"},
+	{groupVersion: "extensions/v1beta1", kinds: []string{"Ingress"}, deprecated: "1.14", removed: "1.22", replacement: "networking.k8s.io/v1"},
+
+	{groupVersion: "flowcontrol.apiserver.k8s.io/v1", kinds: []string{"FlowSchema", "PriorityLevelConfiguration"}, introduced: "1.29"},
+	{groupVersion: "flowcontrol.apiserver.k8s.io/v1beta1", kinds: []string{"FlowSchema", "PriorityLevelConfiguration"}, introduced: "1.20", deprecated: "1.23", removed: "1.26", replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	{groupVersion: "flowcontrol.apiserver.k8s.io/v1beta2", kinds: []string{"FlowSchema", "PriorityLevelConfiguration"}, introduced: "1.23", deprecated: "1.26", removed: "1.29", replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	{groupVersion: "flowcontrol.apiserver.k8s.io/v1beta3", kinds: []string{"FlowSchema", "PriorityLevelConfiguration"}, introduced: "1.26", deprecated: "1.29", removed: "1.32", replacement: "flowcontrol.apiserver.k8s.io/v1"},
+
+	{groupVersion: "networking.k8s.io/v1", kinds: []string{"NetworkPolicy"}},
+	{groupVersion: "networking.k8s.io/v1", kinds: []string{"Ingress", "IngressClass"}, introduced: "1.19"},
+	{groupVersion: "networking.k8s.io/v1beta1", kinds: []string{"Ingress"}, deprecated: "1.19", removed: "1.22", replacement: "networking.k8s.io/v1"},
+	{groupVersion: "networking.k8s.io/v1beta1", kinds: []string{"IngressClass"}, introduced: "1.18", deprecated: "1.19", removed: "1.22", replacement: "networking.k8s.io/v1"},
+
+	{groupVersion: "node.k8s.io/v1", kinds: []string{"RuntimeClass"}, introduced: "1.20"},
+	{groupVersion: "node.k8s.io/v1beta1", kinds: []string{"RuntimeClass"}, deprecated: "1.20", removed: "1.25", replacement: "node.k8s.io/v1"},
+
+	{groupVersion: "policy/v1", kinds: []string{"PodDisruptionBudget"}, introduced: "1.21"},
+	{groupVersion: "policy/v1beta1", kinds: []string{"PodDisruptionBudget"}, deprecated: "1.21", removed: "1.25", replacement: "policy/v1"},
+	{groupVersion: "policy/v1beta1", kinds: []string{"PodSecurityPolicy"}, deprecated: "1.21", removed: "1.25", replacement: "Pod Security Admission"},
+
+	{groupVersion: "rbac.authorization.k8s.io/v1", kinds: []string{"ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding"}},
+	{groupVersion: "rbac.authorization.k8s.io/v1beta1", kinds: []string{"ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding"}, deprecated: "1.17", removed: "1.22", replacement: "rbac.authorization.k8s.io/v1"},
+
+	{groupVersion: "scheduling.k8s.io/v1", kinds: []string{"PriorityClass"}},
+	{groupVersion: "scheduling.k8s.io/v1beta1", kinds: []string{"PriorityClass"}, deprecated: "1.14", removed: "1.22", replacement: "scheduling.k8s.io/v1"},
+
+	{groupVersion: "storage.k8s.io/v1", kinds: []string{"StorageClass", "VolumeAttachment"}},
+	{groupVersion: "storage.k8s.io/v1", kinds: []string{"CSINode"}, introduced: "1.17"},
+	{groupVersion: "storage.k8s.io/v1", kinds: []string{"CSIDriver"}, introduced: "1.18"},
+	{groupVersion: "storage.k8s.io/v1", kinds: []string{"CSIStorageCapacity"}, introduced: "1.24"},
+	{groupVersion: "storage.k8s.io/v1beta1", kinds: []string{"CSIDriver", "CSINode", "StorageClass", "VolumeAttachment"}, deprecated: "1.19", removed: "1.22", replacement: "storage.k8s.io/v1"},
+	{groupVersion: "storage.k8s.io/v1beta1", kinds: []string{"CSIStorageCapacity"}, introduced: "1.21", deprecated: "1.24", removed: "1.27", replacement: "storage.k8s.io/v1"},
+}
+
+// SupportedKubeVersions returns the versions of Kubernetes (i.e. v1.25.0) whose served APIs are known to Hull, in ascending order
+func SupportedKubeVersions() []string {
+	var kubeVersions []string
+	for minor := minSupportedKubeMinor; minor <= maxSupportedKubeMinor; minor++ {
+		kubeVersions = append(kubeVersions, fmt.Sprintf("v1.%d.0", minor))
+	}
+	return kubeVersions
+}
+
+// KubeAPIVersions returns the group versions (i.e. apps/v1) and group version kinds (i.e. apps/v1/Deployment) served by
+// default by the provided version of Kubernetes, in sorted order
+//
+// Versions newer than the newest supported version are assumed to serve the same APIs as the newest supported version.
+func KubeAPIVersions(kubeVersion string) (helmChartUtil.VersionSet, error) {
+	version, err := semver.NewVersion(kubeVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeVersion %s: %s", kubeVersion, err)
+	}
+	if version.Major() != 1 || version.Minor() < minSupportedKubeMinor {
+		return nil, fmt.Errorf("served APIs are not known for Kubernetes %s: must be at least v1.%d", kubeVersion, minSupportedKubeMinor)
+	}
+	apiVersions := map[string]bool{}
+	for _, resource := range apiResources {
+		if !resource.servedIn(version) {
+			continue
+		}
+		apiVersions[resource.groupVersion] = true
+		for _, kind := range resource.kinds {
+			apiVersions[resource.groupVersion+"/"+kind] = true
+		}
+	}
+	versionSet := make(helmChartUtil.VersionSet, 0, len(apiVersions))
+	for apiVersion := range apiVersions {
+		versionSet = append(versionSet, apiVersion)
+	}
+	sort.Strings(versionSet)
+	return versionSet, nil
+}
+
+func (r apiResource) servedIn(version *semver.Version) bool {
+	if len(r.introduced) > 0 && version.LessThan(semver.MustParse(r.introduced)) {
+		return false
+	}
+	if len(r.removed) > 0 && !version.LessThan(semver.MustParse(r.removed)) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package chart
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKubeAPIVersions(t *testing.T) {
+	testCases := []struct {
+		Name             string
+		KubeVersion      string
+		ShouldThrowError bool
+		ExpectServed     []string
+		ExpectNotServed  []string
+	}{
+		{
+			Name:        "Kubernetes 1.16",
+			KubeVersion: "v1.16.0",
+			ExpectServed: []string{
+				"v1", "v1/ConfigMap", "apps/v1/Deployment", "extensions/v1beta1/Ingress", "policy/v1beta1/PodSecurityPolicy",
+			},
+			ExpectNotServed: []string{
+				"extensions/v1beta1/Deployment", "apps/v1beta2", "networking.k8s.io/v1/Ingress", "policy/v1/PodDisruptionBudget",
+			},
+		},
+		{
+			Name:        "Kubernetes 1.24",
+			KubeVersion: "v1.24.17",
+			ExpectServed: []string{
+				"networking.k8s.io/v1/Ingress", "policy/v1/PodDisruptionBudget", "policy/v1beta1/PodSecurityPolicy", "batch/v1beta1/CronJob",
+			},
+			ExpectNotServed: []string{
+				"extensions/v1beta1", "extensions/v1beta1/Ingress", "networking.k8s.io/v1beta1/Ingress",
+			},
+		},
+		{
+			Name:        "Kubernetes 1.25",
+			KubeVersion: "1.25.0",
+			ExpectServed: []string{
+				"policy/v1", "policy/v1/PodDisruptionBudget", "batch/v1/CronJob", "autoscaling/v2beta2/HorizontalPodAutoscaler",
+			},
+			ExpectNotServed: []string{
+				"policy/v1beta1", "policy/v1beta1/PodSecurityPolicy", "batch/v1beta1/CronJob", "autoscaling/v2beta1",
+			},
+		},
+		{
+			Name:        "Newer Than Supported",
+			KubeVersion: "v1.40.0",
+			ExpectServed: []string{
+				"apps/v1/Deployment", "flowcontrol.apiserver.k8s.io/v1/FlowSchema",
+			},
+			ExpectNotServed: []string{
+				"flowcontrol.apiserver.k8s.io/v1beta3",
+			},
+		},
+		{
+			Name:             "Older Than Supported",
+			KubeVersion:      "v1.15.0",
+			ShouldThrowError: true,
+		},
+		{
+			Name:             "Invalid",
+			KubeVersion:      "not-a-version",
+			ShouldThrowError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			apiVersions, err := KubeAPIVersions(tc.KubeVersion)
+			if tc.ShouldThrowError {
+				assert.NotNil(t, err)
+				return
+			}
+			if !assert.Nil(t, err) {
+				return
+			}
+			for _, apiVersion := range tc.ExpectServed {
+				assert.True(t, apiVersions.Has(apiVersion), "expected %s to be served", apiVersion)
+			}
+			for _, apiVersion := range tc.ExpectNotServed {
+				assert.False(t, apiVersions.Has(apiVersion), "expected %s to not be served", apiVersion)
+			}
+		})
+	}
+}
+
+func TestSupportedKubeVersions(t *testing.T) {
+	kubeVersions := SupportedKubeVersions()
+	if !assert.NotEmpty(t, kubeVersions) {
+		return
+	}
+	assert.Equal(t, "v1.16.0", kubeVersions[0])
+	for _, kubeVersion := range kubeVersions {
+		_, err := KubeAPIVersions(kubeVersion)
+		assert.Nil(t, err, "served APIs should be known for %s", kubeVersion)
+	}
+}
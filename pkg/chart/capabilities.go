@@ -12,5 +12,9 @@ func toCapabilitiesArgs(capOpts *Capabilities) string {
 	if capOpts == nil || capOpts == (*Capabilities)(helmChartUtil.DefaultCapabilities) {
 		return ""
 	}
-	return fmt.Sprintf("--kube-version '%s'", capOpts.KubeVersion.Version)
+	args := fmt.Sprintf("--kube-version '%s'", capOpts.KubeVersion.Version)
+	for _, apiVersion := range capOpts.APIVersions {
+		args += fmt.Sprintf(" --api-versions '%s'", apiVersion)
+	}
+	return args
 }
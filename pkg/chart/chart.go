@@ -5,17 +5,21 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/rancher/hull/pkg/checker"
 	"github.com/rancher/hull/pkg/parser"
 	"github.com/rancher/wrangler/v3/pkg/objectset"
 	helmChart "helm.sh/helm/v3/pkg/chart"
 	helmLoader "helm.sh/helm/v3/pkg/chart/loader"
 	helmChartUtil "helm.sh/helm/v3/pkg/chartutil"
 	helmEngine "helm.sh/helm/v3/pkg/engine"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 type Chart interface {
 	GetPath() string
 	GetHelmChart() *helmChart.Chart
+	GetCRDs() ([]*apiextensionsv1.CustomResourceDefinition, error)
 
 	RenderValues(opts *TemplateOptions) (helmChartUtil.Values, error)
 	RenderTemplate(opts *TemplateOptions) (Template, error)
@@ -83,6 +87,7 @@ func (c *chart) RenderTemplate(opts *TemplateOptions) (Template, error) {
 	objectsets := map[string]*objectset.ObjectSet{
 		"": objectset.NewObjectSet(),
 	}
+	sources := make(map[runtime.Object]checker.Source)
 	for source, manifestString := range templateYamls {
 		source := strings.SplitN(source, string(filepath.Separator), 2)[1]
 
@@ -107,12 +112,16 @@ func (c *chart) RenderTemplate(opts *TemplateOptions) (Template, error) {
 		}
 		files[source] = manifestString
 		objectsets[source] = manifestOs
+		for obj, objSource := range templateSources(c.Chart, source, manifestOs) {
+			sources[obj] = objSource
+		}
 		objectsets[""] = objectsets[""].Add(manifestOs.All()...)
 	}
 	t := &template{
 		Options:    opts,
 		Files:      files,
 		ObjectSets: objectsets,
+		Sources:    sources,
 		Values:     values,
 	}
 	t.Chart = c
@@ -0,0 +1,48 @@
+package chart
+
+import (
+	"fmt"
+
+	"github.com/rancher/hull/pkg/openapi"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// GetCRDs returns the CustomResourceDefinitions in the crds/ directory of the chart and its subcharts
+func (c *chart) GetCRDs() ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	var crds []*apiextensionsv1.CustomResourceDefinition
+	for _, crdObject := range c.CRDObjects() {
+		fileCRDs, err := openapi.ParseCRDs(string(crdObject.File.Data))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse CRDs in %s: %s", crdObject.Filename, err)
+		}
+		crds = append(crds, fileCRDs...)
+	}
+	return crds, nil
+}
+
+// GetCRDs returns the CustomResourceDefinitions rendered by the chart's templates
+func (t *template) GetCRDs() ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	return openapi.GetCRDs(t.ObjectSets[""])
+}
+
+// loadCRDChart returns the CustomResourceDefinitions in the crds/ directory and default rendered templates of
+// the chart at the provided path, such as a companion chart that installs the CRDs of another chart
+func loadCRDChart(path string) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	c, err := NewChart(path)
+	if err != nil {
+		return nil, err
+	}
+	crds, err := c.GetCRDs()
+	if err != nil {
+		return nil, err
+	}
+	t, err := c.RenderTemplate(nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render CRD chart %s: %s", path, err)
+	}
+	renderedCRDs, err := t.GetCRDs()
+	if err != nil {
+		return nil, err
+	}
+	return append(crds, renderedCRDs...), nil
+}
@@ -0,0 +1,64 @@
+package chart
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestGetCRDs(t *testing.T) {
+	testCases := []struct {
+		Name               string
+		ChartPath          string
+		ExpectChartCRDs    []string
+		ExpectRenderedCRDs []string
+	}{
+		{
+			Name:      "No CRDs",
+			ChartPath: exampleChartPath,
+		},
+		{
+			Name:            "CRDs Directory",
+			ChartPath:       crdChartPath,
+			ExpectChartCRDs: []string{"gadgets.hull.cattle.io"},
+		},
+		{
+			Name:               "Rendered CRDs",
+			ChartPath:          crdChartCRDPath,
+			ExpectRenderedCRDs: []string{"widgets.hull.cattle.io"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			c, err := NewChart(tc.ChartPath)
+			if !assert.NoError(t, err) {
+				return
+			}
+			chartCRDs, err := c.GetCRDs()
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.ExpectChartCRDs, crdNames(chartCRDs))
+
+			template := getTemplate(t, tc.ChartPath, nil)
+			if template == nil {
+				return
+			}
+			renderedCRDs, err := template.GetCRDs()
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.ExpectRenderedCRDs, crdNames(renderedCRDs))
+		})
+	}
+}
+
+func crdNames(crds []*apiextensionsv1.CustomResourceDefinition) []string {
+	var names []string
+	for _, crd := range crds {
+		names = append(names, crd.Name)
+	}
+	return names
+}
@@ -0,0 +1,20 @@
+package chart
+
+import (
+	"github.com/rancher/hull/pkg/checker"
+	"github.com/rancher/wrangler/v3/pkg/objectset"
+)
+
+// DiffTemplates computes the semantic checker.Diff between all the objects rendered in two Templates
+//
+// Objects are matched across both Templates by GVK, namespace, and name.
+func DiffTemplates(a, b Template) (*checker.Diff, error) {
+	return checker.DiffObjectSets(getRootObjectSet(a), getRootObjectSet(b))
+}
+
+func getRootObjectSet(t Template) *objectset.ObjectSet {
+	if t == nil {
+		return nil
+	}
+	return t.GetObjectSets()[""]
+}
@@ -0,0 +1,69 @@
+package chart
+
+import (
+	"testing"
+
+	"github.com/rancher/hull/pkg/checker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTemplates(t *testing.T) {
+	defaults := getTemplate(t, exampleChartPath, nil)
+	withArgs := getTemplate(t, exampleChartPath, NewTemplateOptions("", "").SetValue("args[0]", "--debug"))
+	withoutRBAC := getTemplate(t, exampleChartPath, NewTemplateOptions("", "").SetValue("global.rbac.userRoles.create", "false"))
+	if t.Failed() {
+		return
+	}
+
+	testCases := []struct {
+		Name   string
+		A      Template
+		B      Template
+		Expect []string
+	}{
+		{
+			Name:   "Same Template",
+			A:      defaults,
+			B:      defaults,
+			Expect: nil,
+		},
+		{
+			Name: "Changed Args",
+			A:    defaults,
+			B:    withArgs,
+			Expect: []string{
+				"~ apps/v1, Kind=Deployment default/example-chart .spec.template.spec.containers[0].args",
+			},
+		},
+		{
+			Name: "Removed User Roles",
+			A:    defaults,
+			B:    withoutRBAC,
+			Expect: []string{
+				"- rbac.authorization.k8s.io/v1, Kind=ClusterRole example-chart-admin",
+				"- rbac.authorization.k8s.io/v1, Kind=ClusterRole example-chart-edit",
+				"- rbac.authorization.k8s.io/v1, Kind=ClusterRole example-chart-view",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			diff, err := DiffTemplates(tc.A, tc.B)
+			if !assert.NoError(t, err) {
+				return
+			}
+			checker.ExpectDiff(t, diff, tc.Expect)
+		})
+	}
+
+	t.Run("Nil Template", func(t *testing.T) {
+		diff, err := DiffTemplates(nil, defaults)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Empty(t, diff.Changed)
+		assert.Empty(t, diff.Removed)
+		assert.NotEmpty(t, diff.Added)
+	})
+}
@@ -16,6 +16,8 @@ import (
 	"github.com/rancher/wrangler/v3/pkg/objectset"
 	helmAction "helm.sh/helm/v3/pkg/action"
 	helmLintSupport "helm.sh/helm/v3/pkg/lint/support"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 //go:embed configuration/yamllint.yaml
@@ -28,10 +30,15 @@ type Template interface {
 	GetOptions() *TemplateOptions
 	GetFiles() map[string]string
 	GetObjectSets() map[string]*objectset.ObjectSet
+	GetSources() map[runtime.Object]checker.Source
 	GetValues() map[string]interface{}
+	GetCRDs() ([]*apiextensionsv1.CustomResourceDefinition, error)
 
 	YamlLint(t *testing.T, yamllintConf string)
 	HelmLint(t *testing.T, opts *HelmLintOptions)
+	Snapshot(t *testing.T, snapshotDir string, update bool)
+	ValidateSchema(t *testing.T, opts *SchemaOptions)
+	CheckDeprecatedAPIs(t *testing.T, opts *DeprecatedAPIOptions)
 }
 
 type template struct {
@@ -40,6 +47,7 @@ type template struct {
 
 	Files      map[string]string
 	ObjectSets map[string]*objectset.ObjectSet
+	Sources    map[runtime.Object]checker.Source
 	Values     map[string]interface{}
 }
 
@@ -62,6 +70,10 @@ func (t *template) GetObjectSets() map[string]*objectset.ObjectSet {
 	return t.ObjectSets
 }
 
+func (t *template) GetSources() map[runtime.Object]checker.Source {
+	return t.Sources
+}
+
 func (t *template) GetValues() map[string]interface{} {
 	return t.Values
 }
@@ -206,7 +218,7 @@ func (t *template) Check(tT *testing.T, objStructFunc checker.CheckFunc) {
 	if t.ObjectSets == nil {
 		return
 	}
-	check, err := checker.NewChecker(t.ObjectSets)
+	check, err := checker.NewCheckerWithSources(t.ObjectSets, t.Sources)
 	if err != nil {
 		tT.Error(err)
 		return
@@ -0,0 +1,105 @@
+package chart
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DeprecatedAPIOptions configures checking rendered objects for built-in APIs that are deprecated or not served by the
+// KubeVersion of the Template
+type DeprecatedAPIOptions struct {
+	// FailOnDeprecated fails the check on APIs that are deprecated but still served instead of logging a warning
+	FailOnDeprecated bool
+}
+
+func findAPIResource(gvk schema.GroupVersionKind) (apiResource, bool) {
+	groupVersion := gvk.GroupVersion().String()
+	for _, resource := range apiResources {
+		if resource.groupVersion != groupVersion {
+			continue
+		}
+		for _, kind := range resource.kinds {
+			if kind == gvk.Kind {
+				return resource, true
+			}
+		}
+	}
+	return apiResource{}, false
+}
+
+func (t *template) CheckDeprecatedAPIs(tT *testing.T, opts *DeprecatedAPIOptions) {
+	if opts == nil {
+		opts = &DeprecatedAPIOptions{}
+	}
+	kubeVersion := t.GetOptions().Capabilities.KubeVersion.Version
+	version, err := semver.NewVersion(kubeVersion)
+	if err != nil {
+		tT.Errorf("invalid kubeVersion %s: %s", kubeVersion, err)
+		return
+	}
+	var templateFiles []string
+	for templateFile := range t.ObjectSets {
+		if len(templateFile) == 0 {
+			continue
+		}
+		templateFiles = append(templateFiles, templateFile)
+	}
+	sort.Strings(templateFiles)
+	for _, templateFile := range templateFiles {
+		t.checkDeprecatedAPIs(tT, opts, version, templateFile)
+	}
+}
+
+func (t *template) checkDeprecatedAPIs(tT *testing.T, opts *DeprecatedAPIOptions, version *semver.Version, templateFile string) {
+	var unserved, deprecated []string
+	for gvk, objsByKey := range t.ObjectSets[templateFile].ObjectsByGVK() {
+		resource, ok := findAPIResource(gvk)
+		if !ok {
+			continue
+		}
+		served := resource.servedIn(version)
+		var reason string
+		switch {
+		case !served && len(resource.introduced) > 0 && version.LessThan(semver.MustParse(resource.introduced)):
+			reason = fmt.Sprintf("not served until v%s", resource.introduced)
+		case !served:
+			reason = fmt.Sprintf("removed in v%s", resource.removed)
+		case len(resource.deprecated) > 0 && !version.LessThan(semver.MustParse(resource.deprecated)):
+			reason = fmt.Sprintf("deprecated in v%s", resource.deprecated)
+			if len(resource.removed) > 0 {
+				reason += fmt.Sprintf(" and removed in v%s", resource.removed)
+			}
+		default:
+			continue
+		}
+		if len(resource.replacement) > 0 {
+			reason += fmt.Sprintf("; use %s instead", resource.replacement)
+		}
+		for key := range objsByKey {
+			message := fmt.Sprintf("%s %s: %s", gvk, key, reason)
+			if served {
+				deprecated = append(deprecated, message)
+			} else {
+				unserved = append(unserved, message)
+			}
+		}
+	}
+	sort.Strings(unserved)
+	sort.Strings(deprecated)
+	if len(unserved) > 0 {
+		tT.Errorf("[%s@%s] %s uses APIs that are not served by Kubernetes %s:\n%s", t.Chart.Metadata.Name, t.Chart.Metadata.Version, templateFile, t.Options.Capabilities.KubeVersion.Version, strings.Join(unserved, "\n"))
+	}
+	if len(deprecated) == 0 {
+		return
+	}
+	if opts.FailOnDeprecated {
+		tT.Errorf("[%s@%s] %s uses APIs that are deprecated in Kubernetes %s:\n%s", t.Chart.Metadata.Name, t.Chart.Metadata.Version, templateFile, t.Options.Capabilities.KubeVersion.Version, strings.Join(deprecated, "\n"))
+		return
+	}
+	tT.Logf("[%s@%s] warning: %s uses APIs that are deprecated in Kubernetes %s:\n%s", t.Chart.Metadata.Name, t.Chart.Metadata.Version, templateFile, t.Options.Capabilities.KubeVersion.Version, strings.Join(deprecated, "\n"))
+}
@@ -0,0 +1,72 @@
+package chart
+
+import (
+	"testing"
+
+	"github.com/rancher/hull/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	deprecatedAPIsChartPath = utils.MustGetPathFromModuleRoot("testdata", "charts", "deprecated-apis-chart")
+)
+
+func TestCheckDeprecatedAPIs(t *testing.T) {
+	testCases := []struct {
+		Name            string
+		TemplateOptions *TemplateOptions
+		Options         *DeprecatedAPIOptions
+		ShouldFail      bool
+	}{
+		{
+			Name:            "Served APIs",
+			TemplateOptions: NewTemplateOptions("deprecated-apis-chart", "default").SetKubeVersion("v1.21.0"),
+		},
+		{
+			Name:            "APIs Not Yet Served",
+			TemplateOptions: NewTemplateOptions("deprecated-apis-chart", "default").SetKubeVersion("v1.20.0"),
+			ShouldFail:      true,
+		},
+		{
+			Name:            "Deprecated API",
+			TemplateOptions: NewTemplateOptions("deprecated-apis-chart", "default").SetKubeVersion("v1.24.0").SetValue("pdbAPIVersion", "policy/v1beta1"),
+		},
+		{
+			Name:            "Deprecated API With FailOnDeprecated",
+			TemplateOptions: NewTemplateOptions("deprecated-apis-chart", "default").SetKubeVersion("v1.24.0").SetValue("pdbAPIVersion", "policy/v1beta1"),
+			Options: &DeprecatedAPIOptions{
+				FailOnDeprecated: true,
+			},
+			ShouldFail: true,
+		},
+		{
+			Name:            "API Not Yet Deprecated With FailOnDeprecated",
+			TemplateOptions: NewTemplateOptions("deprecated-apis-chart", "default").SetKubeVersion("v1.20.0").SetValue("pdbAPIVersion", "policy/v1beta1").SetValue("cronJobAPIVersion", "batch/v1beta1"),
+			Options: &DeprecatedAPIOptions{
+				FailOnDeprecated: true,
+			},
+		},
+		{
+			Name:            "Removed API",
+			TemplateOptions: NewTemplateOptions("deprecated-apis-chart", "default").SetKubeVersion("v1.25.0").SetValue("cronJobAPIVersion", "batch/v1beta1"),
+			ShouldFail:      true,
+		},
+		{
+			Name:            "Removed API With Kube Release",
+			TemplateOptions: NewTemplateOptions("deprecated-apis-chart", "default").WithKubeRelease("1.29").SetValue("pdbAPIVersion", "policy/v1beta1"),
+			ShouldFail:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			template := getTemplate(t, deprecatedAPIsChartPath, tc.TemplateOptions)
+			if template == nil {
+				return
+			}
+			fakeT := &testing.T{}
+			template.CheckDeprecatedAPIs(fakeT, tc.Options)
+			assert.Equal(t, tc.ShouldFail, fakeT.Failed())
+		})
+	}
+}
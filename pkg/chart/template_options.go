@@ -3,6 +3,7 @@ package chart
 import (
 	"fmt"
 
+	"github.com/Masterminds/semver/v3"
 	helmChartUtil "helm.sh/helm/v3/pkg/chartutil"
 )
 
@@ -30,10 +31,43 @@ func (o *TemplateOptions) SetKubeVersion(version string) *TemplateOptions {
 	if o.Capabilities == nil {
 		o.Capabilities = &Capabilities{}
 	}
+	o.copyDefaultCapabilities()
 	o.Capabilities.KubeVersion = *kubeVersion
 	return o
 }
 
+// SetAPIVersions sets .Capabilities.APIVersions to exactly the provided group versions (i.e. apps/v1) and group version kinds (i.e. apps/v1/Deployment)
+func (o *TemplateOptions) SetAPIVersions(apiVersions ...string) *TemplateOptions {
+	if o.Capabilities == nil {
+		o.Capabilities = (*Capabilities)(helmChartUtil.DefaultCapabilities)
+	}
+	o.copyDefaultCapabilities()
+	o.Capabilities.APIVersions = append(helmChartUtil.VersionSet{}, apiVersions...)
+	return o
+}
+
+// WithKubeRelease sets .Capabilities.KubeVersion to the provided release of Kubernetes (i.e. 1.25) and .Capabilities.APIVersions
+// to the APIs served by default by that release
+func (o *TemplateOptions) WithKubeRelease(release string) *TemplateOptions {
+	kubeVersion, err := semver.NewVersion(release)
+	if err != nil {
+		panic(fmt.Errorf("invalid Kubernetes release %s provided: %s", release, err))
+	}
+	apiVersions, err := KubeAPIVersions(kubeVersion.String())
+	if err != nil {
+		panic(err)
+	}
+	return o.SetKubeVersion("v" + kubeVersion.String()).SetAPIVersions(apiVersions...)
+}
+
+// copyDefaultCapabilities ensures that modifying the capabilities of the TemplateOptions does not modify Helm's defaults
+func (o *TemplateOptions) copyDefaultCapabilities() {
+	if o.Capabilities == (*Capabilities)(helmChartUtil.DefaultCapabilities) {
+		defaultCapabilities := *helmChartUtil.DefaultCapabilities
+		o.Capabilities = (*Capabilities)(&defaultCapabilities)
+	}
+}
+
 func (o *TemplateOptions) SetValue(key, value string) *TemplateOptions {
 	o.Values = o.Values.SetValue(key, value)
 	return o
@@ -49,6 +83,17 @@ func (o *TemplateOptions) IsUpgrade(isUpgrade bool) *TemplateOptions {
 	return o
 }
 
+// ForUpgrade returns a copy of the TemplateOptions that renders the chart as an upgrade of an existing release
+func (o *TemplateOptions) ForUpgrade() *TemplateOptions {
+	if o == nil {
+		o = &TemplateOptions{}
+	}
+	upgradeOpts := *o
+	upgradeOpts.Release.IsInstall = false
+	upgradeOpts.Release.IsUpgrade = true
+	return &upgradeOpts
+}
+
 func (o *TemplateOptions) setDefaults(chart string) *TemplateOptions {
 	if o == nil {
 		o = &TemplateOptions{}
@@ -0,0 +1,150 @@
+package chart
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/rancher/hull/pkg/openapi"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// SchemaOptions configures validating rendered objects against OpenAPI schemas
+//
+// Custom resources are always validated against the CRDs in the chart's crds/ directories and the CRDs rendered by
+// the chart's templates, in addition to any CRDs provided by CRDChartPaths or CRDPaths.
+type SchemaOptions struct {
+	// KubernetesSchemaPath is the path to an OpenAPI v2 spec (i.e. the output of `kubectl get --raw /openapi/v2`) to
+	// validate built-in resources against instead of the schemas bundled for the configured KubeVersion
+	KubernetesSchemaPath string
+
+	// CRDChartPaths are paths to charts whose crds/ directory and default rendered templates contain additional
+	// CustomResourceDefinitions, such as a companion chart that installs the CRDs used by this chart
+	CRDChartPaths []string
+
+	// CRDPaths are paths to files or directories containing additional CustomResourceDefinitions
+	CRDPaths []string
+
+	// crdChartCRDs are the CRDs of the CRDChartPaths, which are loaded the first time that the SchemaOptions are used
+	crdChartCRDs []*apiextensionsv1.CustomResourceDefinition
+}
+
+// loadCRDCharts returns the CRDs of the CRDChartPaths, which are only loaded once so that every template validated with
+// the SchemaOptions (i.e. every Case of a Suite) does not render the CRD charts again
+func (o *SchemaOptions) loadCRDCharts() ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	if o.crdChartCRDs != nil {
+		return o.crdChartCRDs, nil
+	}
+	crds := []*apiextensionsv1.CustomResourceDefinition{}
+	for _, crdChartPath := range o.CRDChartPaths {
+		crdChartCRDs, err := loadCRDChart(crdChartPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load CRDs from chart %s: %s", crdChartPath, err)
+		}
+		crds = append(crds, crdChartCRDs...)
+	}
+	o.crdChartCRDs = crds
+	return crds, nil
+}
+
+func (t *template) loadSchemas(opts *SchemaOptions) (*openapi.Schemas, error) {
+	if opts == nil {
+		opts = &SchemaOptions{}
+	}
+	var schemas *openapi.Schemas
+	var err error
+	if len(opts.KubernetesSchemaPath) > 0 {
+		schemas, err = openapi.LoadSchemasFromFile(opts.KubernetesSchemaPath)
+	} else {
+		schemas, err = openapi.LoadKubernetesSchemas(t.GetOptions().Capabilities.KubeVersion.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	chartCRDs, err := t.Chart.GetCRDs()
+	if err != nil {
+		return nil, err
+	}
+	renderedCRDs, err := t.GetCRDs()
+	if err != nil {
+		return nil, err
+	}
+	crds := append(chartCRDs, renderedCRDs...)
+	crdChartCRDs, err := opts.loadCRDCharts()
+	if err != nil {
+		return nil, err
+	}
+	crds = append(crds, crdChartCRDs...)
+	extraCRDs, err := openapi.LoadCRDs(opts.CRDPaths...)
+	if err != nil {
+		return nil, err
+	}
+	crds = append(crds, extraCRDs...)
+	if err := schemas.AddCRDs(crds...); err != nil {
+		return nil, err
+	}
+	return schemas, nil
+}
+
+func (t *template) ValidateSchema(tT *testing.T, opts *SchemaOptions) {
+	if opts == nil {
+		opts = &SchemaOptions{}
+	}
+	schemas, err := t.loadSchemas(opts)
+	if err != nil {
+		tT.Errorf("failed to load schemas: %s", err)
+		return
+	}
+	if kubeVersion := t.GetOptions().Capabilities.KubeVersion; len(opts.KubernetesSchemaPath) == 0 && schemas.KubeVersion != fmt.Sprintf("v%s.%s", kubeVersion.Major, kubeVersion.Minor) {
+		tT.Logf("validating built-in resources against the schemas bundled for Kubernetes %s, the closest bundled version to kubeVersion %s (bundled versions are %s)", schemas.KubeVersion, kubeVersion.Version, strings.Join(openapi.BundledKubeVersions(), ", "))
+	}
+	var templateFiles []string
+	for templateFile := range t.ObjectSets {
+		if len(templateFile) == 0 {
+			continue
+		}
+		templateFiles = append(templateFiles, templateFile)
+	}
+	sort.Strings(templateFiles)
+	for _, templateFile := range templateFiles {
+		t.validateSchema(tT, schemas, templateFile)
+	}
+}
+
+func (t *template) validateSchema(tT *testing.T, schemas *openapi.Schemas, templateFile string) {
+	var failures []string
+	for gvk, objsByKey := range t.ObjectSets[templateFile].ObjectsByGVK() {
+		if !schemas.Has(gvk) {
+			if crd, ok := schemas.CRDFor(gvk.GroupKind()); ok {
+				for key := range objsByKey {
+					failures = append(failures, fmt.Sprintf("%s %s: version %s is not served by CRD %s", gvk, key, gvk.Version, crd))
+				}
+				continue
+			}
+			tT.Logf("[%s@%s] %s: skipping schema validation of %s since no schema was found", t.Chart.Metadata.Name, t.Chart.Metadata.Version, templateFile, gvk)
+			continue
+		}
+		for key, obj := range objsByKey {
+			u, ok := obj.(*unstructured.Unstructured)
+			if !ok {
+				uObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+				if err != nil {
+					tT.Errorf("unable to convert %s %s to unstructured: %s", gvk, key, err)
+					continue
+				}
+				u = &unstructured.Unstructured{Object: uObj}
+			}
+			for _, fieldErr := range schemas.Validate(u) {
+				failures = append(failures, fmt.Sprintf("%s %s %s", gvk, key, fieldErr))
+			}
+		}
+	}
+	if len(failures) == 0 {
+		return
+	}
+	sort.Strings(failures)
+	tT.Errorf("[%s@%s] %s failed schema validation against %s:\n%s", t.Chart.Metadata.Name, t.Chart.Metadata.Version, templateFile, t.Options, strings.Join(failures, "\n"))
+}
@@ -0,0 +1,211 @@
+package chart
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rancher/hull/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	schemaChartPath = utils.MustGetPathFromModuleRoot("testdata", "charts", "schema-chart")
+	crdsPath        = utils.MustGetPathFromModuleRoot("testdata", "crds")
+	crdChartPath    = utils.MustGetPathFromModuleRoot("testdata", "charts", "crd-chart")
+	crdChartCRDPath = utils.MustGetPathFromModuleRoot("testdata", "charts", "crd-chart-crd")
+)
+
+func TestValidateSchema(t *testing.T) {
+	testCases := []struct {
+		Name            string
+		TemplateOptions *TemplateOptions
+		SchemaOptions   *SchemaOptions
+		ShouldFail      bool
+	}{
+		{
+			Name:            "Default",
+			TemplateOptions: NewTemplateOptions("schema-chart", "default"),
+			SchemaOptions: &SchemaOptions{
+				CRDPaths: []string{crdsPath},
+			},
+		},
+		{
+			Name:            "Without CRDs",
+			TemplateOptions: NewTemplateOptions("schema-chart", "default").SetValue("widget.size", "huge"),
+		},
+		{
+			Name:            "Newer Kube Version",
+			TemplateOptions: NewTemplateOptions("schema-chart", "default").SetKubeVersion("v1.28.0"),
+			SchemaOptions: &SchemaOptions{
+				CRDPaths: []string{crdsPath},
+			},
+		},
+		{
+			Name:            "Misspelled Field",
+			TemplateOptions: NewTemplateOptions("schema-chart", "default").SetKubeVersion("v1.27.0").SetValue("extraDeploymentSpec.minReadySecond", "10"),
+			ShouldFail:      true,
+		},
+		{
+			Name:            "Wrong Type",
+			TemplateOptions: NewTemplateOptions("schema-chart", "default").SetKubeVersion("v1.23.0").SetValue("replicas", "one"),
+			ShouldFail:      true,
+		},
+		{
+			Name:            "Wrong Type Without Bundled Schemas For Kube Version",
+			TemplateOptions: NewTemplateOptions("schema-chart", "default").SetKubeVersion("v1.30.0").SetValue("replicas", "one"),
+			ShouldFail:      true,
+		},
+		{
+			Name:            "Wrong Type With Default Kube Version",
+			TemplateOptions: NewTemplateOptions("schema-chart", "default").SetValue("replicas", "one"),
+			ShouldFail:      true,
+		},
+		{
+			Name:            "Invalid Custom Resource",
+			TemplateOptions: NewTemplateOptions("schema-chart", "default").SetValue("widget.size", "huge"),
+			SchemaOptions: &SchemaOptions{
+				CRDPaths: []string{crdsPath},
+			},
+			ShouldFail: true,
+		},
+		{
+			Name:            "Missing CRD Path",
+			TemplateOptions: NewTemplateOptions("schema-chart", "default"),
+			SchemaOptions: &SchemaOptions{
+				CRDPaths: []string{"does-not-exist"},
+			},
+			ShouldFail: true,
+		},
+		{
+			Name:            "Missing Kubernetes Schema Path",
+			TemplateOptions: NewTemplateOptions("schema-chart", "default"),
+			SchemaOptions: &SchemaOptions{
+				KubernetesSchemaPath: "does-not-exist.json",
+			},
+			ShouldFail: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			template := getTemplate(t, schemaChartPath, tc.TemplateOptions)
+			if template == nil {
+				return
+			}
+			fakeT := &testing.T{}
+			template.ValidateSchema(fakeT, tc.SchemaOptions)
+			assert.Equal(t, tc.ShouldFail, fakeT.Failed())
+		})
+	}
+
+}
+
+func TestValidateSchemaCustomResources(t *testing.T) {
+	testCases := []struct {
+		Name            string
+		TemplateOptions *TemplateOptions
+		SchemaOptions   *SchemaOptions
+		ShouldFail      bool
+	}{
+		{
+			Name:            "Default",
+			TemplateOptions: NewTemplateOptions("crd-chart", "default"),
+			SchemaOptions: &SchemaOptions{
+				CRDChartPaths: []string{crdChartCRDPath},
+			},
+		},
+		{
+			Name:            "Without CRD Chart",
+			TemplateOptions: NewTemplateOptions("crd-chart", "default").SetValue("widget.size", "huge"),
+		},
+		{
+			Name:            "Enum",
+			TemplateOptions: NewTemplateOptions("crd-chart", "default").SetValue("gadget.mode", "automatic"),
+			ShouldFail:      true,
+		},
+		{
+			Name:            "Pattern",
+			TemplateOptions: NewTemplateOptions("crd-chart", "default").SetValue("gadget.name", "Hello"),
+			ShouldFail:      true,
+		},
+		{
+			Name:            "Required",
+			TemplateOptions: NewTemplateOptions("crd-chart", "default").SetValue("gadget.mode", "null"),
+			ShouldFail:      true,
+		},
+		{
+			Name:            "Unknown Field",
+			TemplateOptions: NewTemplateOptions("crd-chart", "default").SetValue("gadget.color", "red"),
+			ShouldFail:      true,
+		},
+		{
+			Name:            "Version Not Served",
+			TemplateOptions: NewTemplateOptions("crd-chart", "default").SetValue("gadgetVersion", "v1beta1"),
+			ShouldFail:      true,
+		},
+		{
+			Name:            "CRD From CRD Chart",
+			TemplateOptions: NewTemplateOptions("crd-chart", "default").SetValue("widget.size", "huge"),
+			SchemaOptions: &SchemaOptions{
+				CRDChartPaths: []string{crdChartCRDPath},
+			},
+			ShouldFail: true,
+		},
+		{
+			Name:            "CRD From Path",
+			TemplateOptions: NewTemplateOptions("crd-chart", "default").SetValue("widget.size", "huge"),
+			SchemaOptions: &SchemaOptions{
+				CRDPaths: []string{crdsPath},
+			},
+			ShouldFail: true,
+		},
+		{
+			Name:            "Missing CRD Chart",
+			TemplateOptions: NewTemplateOptions("crd-chart", "default"),
+			SchemaOptions: &SchemaOptions{
+				CRDChartPaths: []string{"does-not-exist"},
+			},
+			ShouldFail: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			template := getTemplate(t, crdChartPath, tc.TemplateOptions)
+			if template == nil {
+				return
+			}
+			fakeT := &testing.T{}
+			template.ValidateSchema(fakeT, tc.SchemaOptions)
+			assert.Equal(t, tc.ShouldFail, fakeT.Failed())
+		})
+	}
+}
+
+func TestValidateSchemaLoadsCRDChartsOnce(t *testing.T) {
+	crdChartDir := filepath.Join(t.TempDir(), "crd-chart-crd")
+	if err := os.CopyFS(crdChartDir, os.DirFS(crdChartCRDPath)); err != nil {
+		t.Fatal(err)
+	}
+	opts := &SchemaOptions{
+		CRDChartPaths: []string{crdChartDir},
+	}
+	template := getTemplate(t, crdChartPath, NewTemplateOptions("crd-chart", "default").SetValue("widget.size", "huge"))
+	if template == nil {
+		return
+	}
+	fakeT := &testing.T{}
+	template.ValidateSchema(fakeT, opts)
+	assert.True(t, fakeT.Failed())
+
+	// the CRD chart is not loaded again, so validation does not fail to load it once it is removed
+	if err := os.RemoveAll(crdChartDir); err != nil {
+		t.Fatal(err)
+	}
+	template = getTemplate(t, crdChartPath, NewTemplateOptions("crd-chart", "default"))
+	if template == nil {
+		return
+	}
+	template.ValidateSchema(t, opts)
+}
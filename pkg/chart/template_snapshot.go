@@ -0,0 +1,132 @@
+package chart
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/rancher/hull/pkg/writer"
+)
+
+func (t *template) Snapshot(tT *testing.T, snapshotDir string, update bool) {
+	if update {
+		if err := t.updateSnapshot(snapshotDir); err != nil {
+			tT.Errorf("failed to update snapshot at %s: %s", snapshotDir, err)
+			return
+		}
+		tT.Logf("updated snapshot at %s", snapshotDir)
+		return
+	}
+	var templateFiles []string
+	for templateFile := range t.Files {
+		templateFiles = append(templateFiles, templateFile)
+	}
+	sort.Strings(templateFiles)
+	for _, templateFile := range templateFiles {
+		t.snapshot(tT, snapshotDir, templateFile)
+	}
+	// identify template files that are in the snapshot but are no longer rendered
+	err := filepath.WalkDir(snapshotDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		templateFile, err := filepath.Rel(snapshotDir, path)
+		if err != nil {
+			return err
+		}
+		templateFile = filepath.ToSlash(templateFile)
+		if _, ok := t.Files[templateFile]; !ok {
+			tT.Errorf("[%s@%s] %s exists in snapshot %s but was not rendered by %s: update snapshots to remove it", t.Chart.Metadata.Name, t.Chart.Metadata.Version, templateFile, snapshotDir, t.Options)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		tT.Error(err)
+	}
+}
+
+func (t *template) snapshot(tT *testing.T, snapshotDir, templateFile string) {
+	actual := normalizeManifest(t.Files[templateFile])
+	snapshotFile := filepath.Join(snapshotDir, filepath.FromSlash(templateFile))
+	expectedBytes, err := os.ReadFile(snapshotFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			tT.Errorf("[%s@%s] %s does not exist in snapshot %s: update snapshots to create it", t.Chart.Metadata.Name, t.Chart.Metadata.Version, templateFile, snapshotDir)
+			return
+		}
+		tT.Error(err)
+		return
+	}
+	expected := normalizeManifest(string(expectedBytes))
+	if expected == actual {
+		return
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(expected),
+		B:        difflib.SplitLines(actual),
+		FromFile: snapshotFile,
+		ToFile:   templateFile,
+		Context:  3,
+	})
+	if err != nil {
+		tT.Error(err)
+		return
+	}
+	tT.Errorf("[%s@%s] %s does not match snapshot against %s: update snapshots to accept changes\n%s", t.Chart.Metadata.Name, t.Chart.Metadata.Version, templateFile, t.Options, diff)
+	w := writer.NewDiffWriter(
+		tT,
+		filepath.Join(t.Chart.Metadata.Name, t.Chart.Metadata.Version, templateFile),
+		t.Options.String(),
+		actual,
+	)
+	if _, err := w.Write([]byte(diff)); err != nil {
+		tT.Error(err)
+	}
+}
+
+func (t *template) updateSnapshot(snapshotDir string) error {
+	if err := os.RemoveAll(snapshotDir); err != nil {
+		return err
+	}
+	for templateFile, raw := range t.Files {
+		snapshotFile := filepath.Join(snapshotDir, filepath.FromSlash(templateFile))
+		if err := os.MkdirAll(filepath.Dir(snapshotFile), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(snapshotFile, []byte(normalizeManifest(raw)), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeManifest removes differences in rendered output that do not affect the rendered objects,
+// such as trailing whitespace, blank lines, and empty YAML documents
+func normalizeManifest(raw string) string {
+	var lines []string
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if len(line) == 0 {
+			continue
+		}
+		if line == "---" && (len(lines) == 0 || lines[len(lines)-1] == "---") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "---" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s\n", strings.Join(lines, "\n"))
+}
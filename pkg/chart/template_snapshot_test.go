@@ -0,0 +1,134 @@
+package chart
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Modify     func(snapshotDir string) error
+		ShouldFail bool
+	}{
+		{
+			Name: "Unmodified",
+		},
+		{
+			Name: "Whitespace Changes",
+			Modify: func(snapshotDir string) error {
+				path := filepath.Join(snapshotDir, "templates", "rbac.yaml")
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				return os.WriteFile(path, append([]byte("---\n\n"), append(data, []byte("  \n---\n")...)...), 0644)
+			},
+		},
+		{
+			Name: "Modified Template",
+			Modify: func(snapshotDir string) error {
+				return os.WriteFile(filepath.Join(snapshotDir, "templates", "rbac.yaml"), []byte("hello: world\n"), 0644)
+			},
+			ShouldFail: true,
+		},
+		{
+			Name: "Missing Template",
+			Modify: func(snapshotDir string) error {
+				return os.Remove(filepath.Join(snapshotDir, "templates", "rbac.yaml"))
+			},
+			ShouldFail: true,
+		},
+		{
+			Name: "Extra Template",
+			Modify: func(snapshotDir string) error {
+				return os.WriteFile(filepath.Join(snapshotDir, "templates", "extra.yaml"), []byte("hello: world\n"), 0644)
+			},
+			ShouldFail: true,
+		},
+		{
+			Name: "Missing Snapshot",
+			Modify: func(snapshotDir string) error {
+				return os.RemoveAll(snapshotDir)
+			},
+			ShouldFail: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			template := getTemplate(t, exampleChartPath, nil)
+			if template == nil {
+				return
+			}
+			snapshotDir := filepath.Join(t.TempDir(), "snapshot")
+
+			template.Snapshot(t, snapshotDir, true)
+			if t.Failed() {
+				return
+			}
+			for templateFile := range template.GetFiles() {
+				assert.FileExists(t, filepath.Join(snapshotDir, templateFile))
+			}
+
+			if tc.Modify != nil {
+				if err := tc.Modify(snapshotDir); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+			fakeT := &testing.T{}
+			template.Snapshot(fakeT, snapshotDir, false)
+			assert.Equal(t, tc.ShouldFail, fakeT.Failed())
+
+			// updating should always fix the snapshot
+			template.Snapshot(t, snapshotDir, true)
+			fakeT = &testing.T{}
+			template.Snapshot(fakeT, snapshotDir, false)
+			assert.False(t, fakeT.Failed(), "expected snapshot to match after update")
+		})
+	}
+}
+
+func TestNormalizeManifest(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Raw    string
+		Expect string
+	}{
+		{
+			Name:   "Empty",
+			Raw:    "",
+			Expect: "",
+		},
+		{
+			Name:   "Only Separators",
+			Raw:    "---\n\n---\n",
+			Expect: "",
+		},
+		{
+			Name:   "Leading Separator",
+			Raw:    "---\nhello: world",
+			Expect: "hello: world\n",
+		},
+		{
+			Name:   "Trailing Whitespace And Blank Lines",
+			Raw:    "hello: world  \n\n\nrancher: hull\t\n",
+			Expect: "hello: world\nrancher: hull\n",
+		},
+		{
+			Name:   "Empty Documents",
+			Raw:    "---\nhello: world\n---\n\n---\nrancher: hull\n---\n",
+			Expect: "hello: world\n---\nrancher: hull\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expect, normalizeManifest(tc.Raw))
+		})
+	}
+}
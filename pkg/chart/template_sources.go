@@ -0,0 +1,139 @@
+package chart
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/rancher/hull/pkg/checker"
+	"github.com/rancher/wrangler/v3/pkg/objectset"
+	helmChart "helm.sh/helm/v3/pkg/chart"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var (
+	topLevelFieldRegex = regexp.MustCompile(`^([A-Za-z][\w.-]*):`)
+	kindRegex          = regexp.MustCompile(`^kind:\s*["']?([A-Za-z]+)["']?\s*$`)
+)
+
+// templateDocument is a YAML document in the source of a template
+type templateDocument struct {
+	// kind is the kind of the document, if it is not templated
+	kind   string
+	lines  checker.LineRange
+	fields map[string]checker.LineRange
+}
+
+// templateSources returns the checker.Source of every object rendered from the template at the path
+//
+// Objects are matched to the YAML documents of the template in order by kind, so the lines of objects rendered from
+// documents that produce several objects (i.e. in a range) or whose documents are separated by templated separators
+// may not be known.
+func templateSources(c *helmChart.Chart, path string, os *objectset.ObjectSet) map[runtime.Object]checker.Source {
+	sources := make(map[runtime.Object]checker.Source)
+	data, ok := templateData(c, path)
+	if !ok {
+		return sources
+	}
+	documents := parseTemplateDocuments(string(data))
+	next := 0
+	var last *templateDocument
+	for _, obj := range os.All() {
+		source := checker.Source{Template: path}
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		var document *templateDocument
+		for i := next; i < len(documents); i++ {
+			if documents[i].matches(u.GetKind()) {
+				document = &documents[i]
+				next = i + 1
+				break
+			}
+		}
+		if document == nil && last != nil && last.matches(u.GetKind()) {
+			// documents in a range render several objects
+			document = last
+		}
+		if document != nil {
+			source.Lines = document.lines
+			source.Fields = document.fields
+			last = document
+		}
+		sources[obj] = source
+	}
+	return sources
+}
+
+// templateData returns the source of the template at the path, which may be in a subchart (i.e.
+// charts/subchart/templates/deployment.yaml)
+func templateData(c *helmChart.Chart, path string) ([]byte, bool) {
+	for _, f := range c.Templates {
+		if f.Name == path {
+			return f.Data, true
+		}
+	}
+	for _, dependency := range c.Dependencies() {
+		prefix := "charts/" + dependency.Name() + "/"
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		if data, ok := templateData(dependency, strings.TrimPrefix(path, prefix)); ok {
+			return data, true
+		}
+	}
+	return nil, false
+}
+
+func parseTemplateDocuments(data string) []templateDocument {
+	var documents []templateDocument
+	document := templateDocument{fields: map[string]checker.LineRange{}}
+	var field string
+	lastContentLine := 0
+	closeField := func() {
+		if len(field) == 0 {
+			return
+		}
+		lines := document.fields[field]
+		lines.End = lastContentLine
+		document.fields[field] = lines
+		field = ""
+	}
+	closeDocument := func() {
+		closeField()
+		if len(document.fields) > 0 {
+			document.lines.End = lastContentLine
+			documents = append(documents, document)
+		}
+		document = templateDocument{fields: map[string]checker.LineRange{}}
+	}
+	for i, line := range strings.Split(data, "\n") {
+		lineNumber := i + 1
+		if strings.HasPrefix(line, "---") {
+			closeDocument()
+			continue
+		}
+		if len(strings.TrimSpace(line)) == 0 || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if match := topLevelFieldRegex.FindStringSubmatch(line); match != nil {
+			closeField()
+			field = match[1]
+			document.fields[field] = checker.LineRange{Start: lineNumber}
+			if document.lines.Start == 0 {
+				document.lines.Start = lineNumber
+			}
+			if kindMatch := kindRegex.FindStringSubmatch(line); kindMatch != nil {
+				document.kind = kindMatch[1]
+			}
+		}
+		lastContentLine = lineNumber
+	}
+	closeDocument()
+	return documents
+}
+
+func (d *templateDocument) matches(kind string) bool {
+	return len(d.kind) == 0 || d.kind == kind
+}
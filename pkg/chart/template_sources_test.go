@@ -0,0 +1,78 @@
+package chart
+
+import (
+	"testing"
+
+	"github.com/rancher/hull/pkg/checker"
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+func TestParseTemplateDocuments(t *testing.T) {
+	data := `{{- if .Values.enabled }}
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: hello
+data:
+  hello: world
+{{- end }}
+---
+# a comment
+{{- range .Values.names }}
+---
+apiVersion: v1
+kind: {{ .kind }}
+metadata:
+  name: {{ .name }}
+
+{{- end }}
+---
+{{/* no fields */}}
+`
+	expected := []templateDocument{
+		{
+			kind:  "ConfigMap",
+			lines: checker.LineRange{Start: 2, End: 8},
+			fields: map[string]checker.LineRange{
+				"apiVersion": {Start: 2, End: 2},
+				"kind":       {Start: 3, End: 3},
+				"metadata":   {Start: 4, End: 5},
+				"data":       {Start: 6, End: 8},
+			},
+		},
+		{
+			lines: checker.LineRange{Start: 13, End: 18},
+			fields: map[string]checker.LineRange{
+				"apiVersion": {Start: 13, End: 13},
+				"kind":       {Start: 14, End: 14},
+				"metadata":   {Start: 15, End: 18},
+			},
+		},
+	}
+	assert.Equal(t, expected, parseTemplateDocuments(data))
+}
+
+func TestTemplateSources(t *testing.T) {
+	template := getTemplate(t, exampleChartPath, NewTemplateOptions("example-chart", "default"))
+	if template == nil {
+		return
+	}
+	objs := map[string]checker.Source{}
+	for _, obj := range template.GetObjectSets()["templates/rbac.yaml"].All() {
+		objs[obj.GetObjectKind().GroupVersionKind().Kind] = template.GetSources()[obj]
+	}
+	assert.Equal(t, "templates/rbac.yaml:1-16", objs["Role"].String())
+	assert.Equal(t, "templates/rbac.yaml:18-33", objs["RoleBinding"].String())
+	assert.Equal(t, "templates/rbac.yaml:25-29", objs["RoleBinding"].Field(".roleRef.name"))
+	assert.Equal(t, "templates/rbac.yaml:18-33", objs["RoleBinding"].Field("status"))
+	assert.Equal(t, "templates/rbac.yaml:35-44", objs["ServiceAccount"].String())
+
+	template.Check(t, checker.NewCheckFunc(
+		checker.NewChainedCheckFunc(func(tc *checker.TestContext, objs struct{ Roles []*rbacv1.Role }) {
+			if assert.Len(t, objs.Roles, 1) {
+				assert.Equal(t, "templates/rbac.yaml:8-16", tc.SourceOf(objs.Roles[0]).Field("rules[0].verbs"))
+			}
+		}),
+	))
+}
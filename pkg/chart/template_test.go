@@ -8,6 +8,7 @@ import (
 	"github.com/rancher/hull/pkg/utils"
 	"github.com/rancher/wrangler/v3/pkg/objectset"
 	"github.com/stretchr/testify/assert"
+	helmChartUtil "helm.sh/helm/v3/pkg/chartutil"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -18,6 +19,7 @@ var (
 	wrongAnnotationsChartPath   = utils.MustGetPathFromModuleRoot("testdata", "charts", "wrong-annotations")
 	wrongOSAnnotationChartPath  = utils.MustGetPathFromModuleRoot("testdata", "charts", "wrong-os-annotation")
 	invalidKubeConstraintPath   = utils.MustGetPathFromModuleRoot("testdata", "charts", "invalid-kube-constraint")
+	kubeVersionChartPath        = utils.MustGetPathFromModuleRoot("testdata", "charts", "kube-version-chart")
 )
 
 func getTemplate(t *testing.T, chartPath string, opts *TemplateOptions) Template {
@@ -219,6 +221,78 @@ func TestSetKubeVersion(t *testing.T) {
 	}
 }
 
+func TestSetAPIVersions(t *testing.T) {
+	opts := NewTemplateOptions("example-chart", "default").SetAPIVersions("apps/v1", "apps/v1/Deployment")
+	assert.Equal(t, helmChartUtil.VersionSet{"apps/v1", "apps/v1/Deployment"}, opts.Capabilities.APIVersions)
+	assert.Equal(t, helmChartUtil.DefaultCapabilities.KubeVersion, opts.Capabilities.KubeVersion)
+	assert.NotEqual(t, helmChartUtil.VersionSet{"apps/v1", "apps/v1/Deployment"}, helmChartUtil.DefaultCapabilities.APIVersions, "default capabilities should not be modified")
+
+	opts = NewTemplateOptions("example-chart", "default").SetKubeVersion("v1.25.0").SetAPIVersions("v1")
+	assert.Equal(t, "v1.25.0", opts.Capabilities.KubeVersion.Version)
+	assert.Equal(t, helmChartUtil.VersionSet{"v1"}, opts.Capabilities.APIVersions)
+}
+
+func TestWithKubeRelease(t *testing.T) {
+	testCases := []struct {
+		Name             string
+		Release          string
+		ShouldThrowError bool
+		ExpectVersion    string
+		ExpectPSP        bool
+	}{
+		{
+			Name:          "Minor",
+			Release:       "1.24",
+			ExpectVersion: "v1.24.0",
+			ExpectPSP:     true,
+		},
+		{
+			Name:          "Patch",
+			Release:       "v1.25.3",
+			ExpectVersion: "v1.25.3",
+			ExpectPSP:     false,
+		},
+		{
+			Name:             "Unsupported",
+			Release:          "1.15",
+			ShouldThrowError: true,
+		},
+		{
+			Name:             "Invalid",
+			Release:          "hello",
+			ShouldThrowError: true,
+		},
+	}
+
+	c, err := NewChart(kubeVersionChartPath)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			defer func() {
+				err := recover()
+				if err != nil {
+					assert.True(t, tc.ShouldThrowError, "unexpected error: %s", err)
+				}
+				if err == nil {
+					assert.False(t, tc.ShouldThrowError, "expected error to be thrown")
+				}
+			}()
+			opts := NewTemplateOptions("kube-version-chart", "default").WithKubeRelease(tc.Release)
+			assert.Equal(t, tc.ExpectVersion, opts.Capabilities.KubeVersion.Version)
+			template, err := c.RenderTemplate(opts)
+			if !assert.Nil(t, err) {
+				return
+			}
+			pspObjectSet := template.GetObjectSets()["templates/psp.yaml"]
+			assert.Equal(t, tc.ExpectPSP, pspObjectSet != nil && pspObjectSet.Len() > 0)
+			assert.Contains(t, template.GetFiles()["templates/pdb.yaml"], "apiVersion: policy/v1\n")
+		})
+	}
+}
+
 func TestTemplateOptionsString(t *testing.T) {
 	testCases := []struct {
 		Name    string
@@ -266,6 +340,11 @@ func TestTemplateOptionsString(t *testing.T) {
 			Options: NewTemplateOptions("world", "hello").SetKubeVersion("1.16.0"),
 			String:  "helm template -n hello --kube-version 'v1.16.0' world <path-to-chart>",
 		},
+		{
+			Name:    "Default With APIVersions",
+			Options: NewTemplateOptions("world", "hello").SetKubeVersion("1.16.0").SetAPIVersions("apps/v1", "apps/v1/Deployment"),
+			String:  "helm template -n hello --kube-version 'v1.16.0' --api-versions 'apps/v1' --api-versions 'apps/v1/Deployment' world <path-to-chart>",
+		},
 		{
 			Name:    "Default With Set Value",
 			Options: NewTemplateOptions("world", "hello").SetValue("rancher", "hull"),
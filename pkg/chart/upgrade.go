@@ -0,0 +1,26 @@
+package chart
+
+import (
+	"testing"
+
+	"github.com/rancher/hull/pkg/checker"
+)
+
+// CheckUpgrade fails the test if upgrading a release from the objects rendered in the from Template
+// to the objects rendered in the to Template would modify fields that are immutable in Kubernetes
+func CheckUpgrade(t *testing.T, from, to Template) {
+	diff, err := DiffTemplates(from, to)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	changes := checker.ImmutableFieldChanges(diff)
+	if len(changes) == 0 {
+		return
+	}
+	fromChart := from.GetChart().GetHelmChart().Metadata
+	toChart := to.GetChart().GetHelmChart().Metadata
+	for _, change := range changes {
+		t.Errorf("[%s@%s -> %s@%s] upgrade against %s would fail: %s", fromChart.Name, fromChart.Version, toChart.Name, toChart.Version, to.GetOptions(), change)
+	}
+}
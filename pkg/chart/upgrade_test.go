@@ -0,0 +1,62 @@
+package chart
+
+import (
+	"testing"
+
+	"github.com/rancher/hull/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	upgradeChartPath         = utils.MustGetPathFromModuleRoot("testdata", "charts", "upgrade-chart")
+	previousUpgradeChartPath = utils.MustGetPathFromModuleRoot("testdata", "charts", "upgrade-chart-previous")
+)
+
+func TestCheckUpgrade(t *testing.T) {
+	testCases := []struct {
+		Name            string
+		TemplateOptions *TemplateOptions
+		ShouldFail      bool
+	}{
+		{
+			Name:            "Default",
+			TemplateOptions: NewTemplateOptions("upgrade-chart", "default"),
+		},
+		{
+			Name:            "Mutable Field Changed",
+			TemplateOptions: NewTemplateOptions("upgrade-chart", "default").SetValue("replicas", "2"),
+		},
+		{
+			Name:            "Immutable Field Changed",
+			TemplateOptions: NewTemplateOptions("upgrade-chart", "default").SetValue("extraSelectorLabels.component", "server"),
+			ShouldFail:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			from := getTemplate(t, previousUpgradeChartPath, tc.TemplateOptions)
+			to := getTemplate(t, upgradeChartPath, tc.TemplateOptions.ForUpgrade())
+			if t.Failed() {
+				return
+			}
+			fakeT := &testing.T{}
+			CheckUpgrade(fakeT, from, to)
+			assert.Equal(t, tc.ShouldFail, fakeT.Failed())
+		})
+	}
+}
+
+func TestForUpgrade(t *testing.T) {
+	opts := NewTemplateOptions("hello", "world").SetValue("hello", "world")
+	upgradeOpts := opts.ForUpgrade()
+	assert.True(t, upgradeOpts.Release.IsUpgrade)
+	assert.False(t, upgradeOpts.Release.IsInstall)
+	assert.Equal(t, opts.Release.Name, upgradeOpts.Release.Name)
+	assert.Equal(t, opts.Release.Namespace, upgradeOpts.Release.Namespace)
+	assert.Equal(t, opts.Values, upgradeOpts.Values)
+	assert.False(t, opts.Release.IsUpgrade, "original options should not be modified")
+
+	var nilOpts *TemplateOptions
+	assert.True(t, nilOpts.ForUpgrade().Release.IsUpgrade)
+}
@@ -9,31 +9,79 @@ import (
 )
 
 func NewCheckFunc(funcs ...ChainedCheckFunc) CheckFunc {
-	return func(t *testing.T, u struct{ Unstructured []*unstructured.Unstructured }) {
+	return newCheckFunc(nil, funcs)
+}
+
+func newCheckFunc(strict *StrictDecoding, funcs []ChainedCheckFunc) CheckFunc {
+	return sourcedCheckFunc(func(t *testing.T, u struct{ Unstructured []*unstructured.Unstructured }, sources ...map[runtime.Object]Source) {
 		tc := NewContext()
 		tc.T = t
-		for _, f := range funcs {
-			checkFunc := f(tc)
-			if checkFunc == nil {
-				if !tc.continueExecution && tc.T.Failed() {
-					break
-				}
-				tc.continueExecution = false
-				continue
-			}
-			doFunc := internal.WrapFunc(checkFunc, &internal.ParseOptions{
-				Scheme: Scheme,
-			})
-			objs := make([]runtime.Object, len(u.Unstructured))
-			for i, unstructured := range u.Unstructured {
-				objs[i] = unstructured
-			}
+		tc.strict = strict
+		tc.objects = u.Unstructured
+		if len(sources) > 0 {
+			tc.sources = sources[0]
+		}
+		if !runChain(tc, funcs) {
+			strict.stopped()
+		}
+	})
+}
+
+// runChain runs the funcs against the objects of the TestContext and returns false if it stopped early due to a failure
+func runChain(tc *TestContext, funcs []ChainedCheckFunc) bool {
+	opts := &internal.ParseOptions{
+		Scheme: Scheme,
+	}
+	if tc.strict != nil {
+		opts.OnParse = tc.strict.record
+	}
+	opts.OnDecode = func(original, obj runtime.Object) {
+		source, ok := tc.sources[original]
+		if !ok {
+			return
+		}
+		if tc.decodedSources == nil {
+			tc.decodedSources = make(map[runtime.Object]Source)
+		}
+		tc.decodedSources[obj] = source
+	}
+	objs := make([]runtime.Object, len(tc.objects))
+	for i, unstructured := range tc.objects {
+		objs[i] = unstructured
+	}
+	for _, f := range funcs {
+		checkFunc := f(tc)
+		if checkFunc != nil {
+			doFunc := wrapFunc(checkFunc, opts, tc.sources)
 			doFunc(tc.T, objs)
-			if !tc.continueExecution && tc.T.Failed() {
-				break
+		}
+		tc.runDeferred()
+		tc.decodedSources = nil
+		if !tc.continueExecution && tc.T.Failed() {
+			return false
+		}
+		tc.continueExecution = false
+	}
+	return true
+}
+
+// InFile runs the funcs against only the objects rendered from the template at the path, i.e. templates/webhook.yaml
+//
+// If the template does not render any objects, the funcs are run against no objects.
+func InFile(path string, funcs ...ChainedCheckFunc) ChainedCheckFunc {
+	return func(tc *TestContext) CheckFunc {
+		objects := tc.objects
+		defer func() {
+			tc.objects = objects
+		}()
+		tc.objects = nil
+		for _, obj := range objects {
+			if tc.SourceOf(obj).Template == path {
+				tc.objects = append(tc.objects, obj)
 			}
-			tc.continueExecution = false
 		}
+		runChain(tc, funcs)
+		return nil
 	}
 }
 
@@ -5,6 +5,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/rancher/hull/pkg/parser"
+	"github.com/rancher/wrangler/v3/pkg/objectset"
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
@@ -247,3 +249,89 @@ func TestNewCheckFunc(t *testing.T) {
 		assert.True(t, fakeT.Failed(), "should have failed")
 	})
 }
+
+func TestInFile(t *testing.T) {
+	webhookOs, err := parser.Parse(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: webhook
+  namespace: cattle-system
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rbacOs, err := parser.Parse(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: rbac
+  namespace: cattle-system
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: rbac
+  namespace: cattle-system
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewChecker(map[string]*objectset.ObjectSet{
+		"templates/webhook.yaml": webhookOs,
+		"templates/rbac.yaml":    rbacOs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configMapNames := func(names *[]string) ChainedCheckFunc {
+		return NewChainedCheckFunc(func(tc *TestContext, objs struct{ ConfigMaps []*corev1.ConfigMap }) {
+			for _, configMap := range objs.ConfigMaps {
+				*names = append(*names, configMap.Name)
+			}
+		})
+	}
+
+	var webhookNames, missingNames, allNames []string
+	c.Check(t, NewCheckFunc(
+		InFile("templates/webhook.yaml", configMapNames(&webhookNames)),
+		InFile("templates/missing.yaml", configMapNames(&missingNames)),
+		configMapNames(&allNames),
+	))
+	assert.Equal(t, []string{"webhook"}, webhookNames)
+	assert.Empty(t, missingNames)
+	assert.ElementsMatch(t, []string{"webhook", "rbac"}, allNames)
+
+	t.Run("Failure Stops Chain", func(t *testing.T) {
+		var ran bool
+		fakeT := &testing.T{}
+		c.Check(fakeT, NewCheckFunc(
+			InFile("templates/rbac.yaml", Once(func(tc *TestContext) {
+				tc.T.Error("failed")
+			})),
+			Once(func(tc *TestContext) {
+				ran = true
+			}),
+		))
+		assert.True(t, fakeT.Failed())
+		assert.False(t, ran)
+	})
+
+	t.Run("Map Field", func(t *testing.T) {
+		c.Check(t, func(t *testing.T, objs struct {
+			ConfigMaps      map[string][]*corev1.ConfigMap
+			ServiceAccounts map[string][]*corev1.ServiceAccount `hull:"name=rbac"`
+		}) {
+			assert.Len(t, objs.ConfigMaps, 2)
+			if assert.Len(t, objs.ConfigMaps["templates/webhook.yaml"], 1) {
+				assert.Equal(t, "webhook", objs.ConfigMaps["templates/webhook.yaml"][0].Name)
+			}
+			if assert.Len(t, objs.ConfigMaps["templates/rbac.yaml"], 1) {
+				assert.Equal(t, "rbac", objs.ConfigMaps["templates/rbac.yaml"][0].Name)
+			}
+			assert.Len(t, objs.ServiceAccounts, 1)
+			assert.Len(t, objs.ServiceAccounts["templates/rbac.yaml"], 1)
+		})
+	})
+}
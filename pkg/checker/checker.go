@@ -6,6 +6,7 @@ import (
 	"github.com/rancher/hull/pkg/checker/internal"
 	"github.com/rancher/hull/pkg/parser"
 	"github.com/rancher/wrangler/v3/pkg/objectset"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 type CheckFunc interface{}
@@ -15,6 +16,13 @@ type Checker interface {
 }
 
 func NewChecker(osMap map[string]*objectset.ObjectSet) (Checker, error) {
+	return NewCheckerWithSources(osMap, nil)
+}
+
+// NewCheckerWithSources returns a Checker whose ChainedCheckFuncs can look up the Source of the objects in the osMap
+// (i.e. the lines of the template that rendered them); objects without a Source are attributed to the template whose
+// path they are keyed by
+func NewCheckerWithSources(osMap map[string]*objectset.ObjectSet, sources map[runtime.Object]Source) (Checker, error) {
 	if osMap == nil {
 		return nil, nil
 	}
@@ -39,6 +47,7 @@ func NewChecker(osMap map[string]*objectset.ObjectSet) (Checker, error) {
 	osMapCopy[""] = rootOs
 	return &checker{
 		ObjectSets: osMapCopy,
+		sources:    sourcesOf(osMapCopy, sources),
 	}, nil
 }
 
@@ -67,14 +76,16 @@ func NewCheckerFromString(rawYaml string, name string) (Checker, error) {
 
 type checker struct {
 	ObjectSets map[string]*objectset.ObjectSet
+
+	sources map[runtime.Object]Source
 }
 
 func (c *checker) Check(t *testing.T, objStructFunc CheckFunc) {
 	if objStructFunc == nil {
 		return
 	}
-	doFunc := internal.WrapFunc(objStructFunc, &internal.ParseOptions{
+	doFunc := wrapFunc(objStructFunc, &internal.ParseOptions{
 		Scheme: Scheme,
-	})
+	}, c.sources)
 	doFunc(t, c.ObjectSets[""].All())
 }
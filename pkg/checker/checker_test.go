@@ -8,7 +8,9 @@ import (
 	"github.com/rancher/hull/pkg/parser"
 	"github.com/rancher/wrangler/v3/pkg/objectset"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 func TestNewChecker(t *testing.T) {
@@ -279,3 +281,148 @@ metadata:
 		assert.Nil(t, nilChecker)
 	})
 }
+
+func TestCheckWithSelectorTags(t *testing.T) {
+	webhookYaml := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: webhook-config
+  namespace: cattle-system
+  labels:
+    app: webhook
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: webhook-ca
+  namespace: cattle-system
+`
+	rbacYaml := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: rbac-config
+  namespace: default
+  labels:
+    app: rbac
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: rbac
+  namespace: default
+`
+	webhookOs, err := parser.Parse(webhookYaml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rbacOs, err := parser.Parse(rbacYaml)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewChecker(map[string]*objectset.ObjectSet{
+		"templates/webhook.yaml": webhookOs,
+		"templates/rbac.yaml":    rbacOs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := func(configMaps []*corev1.ConfigMap) []string {
+		var names []string
+		for _, configMap := range configMaps {
+			names = append(names, configMap.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	var checked bool
+	c.Check(t, func(t *testing.T, objs struct {
+		ConfigMaps        []*corev1.ConfigMap
+		CattleConfigMaps  []*corev1.ConfigMap `hull:"namespace=cattle-system"`
+		WebhookConfigMaps []*corev1.ConfigMap `hull:"name=~^webhook-,label=app=webhook"`
+		RBACConfigMaps    []*corev1.ConfigMap `hull:"template=templates/rbac.yaml"`
+		Unstructured      []*unstructured.Unstructured
+	}) {
+		checked = true
+		assert.Equal(t, []string{"rbac-config", "webhook-ca", "webhook-config"}, names(objs.ConfigMaps))
+		assert.Equal(t, []string{"webhook-ca", "webhook-config"}, names(objs.CattleConfigMaps))
+		assert.Equal(t, []string{"webhook-config"}, names(objs.WebhookConfigMaps))
+		assert.Equal(t, []string{"rbac-config"}, names(objs.RBACConfigMaps))
+		if assert.Len(t, objs.Unstructured, 1) {
+			assert.Equal(t, "ServiceAccount", objs.Unstructured[0].GetKind())
+		}
+	})
+	assert.True(t, checked)
+
+	t.Run("Invalid Tag", func(t *testing.T) {
+		fakeT := &testing.T{}
+		c.Check(fakeT, func(t *testing.T, objs struct {
+			ConfigMaps []*corev1.ConfigMap `hull:"kind=ConfigMap"`
+		}) {
+		})
+		assert.True(t, fakeT.Failed())
+	})
+
+	t.Run("Duplicate Tag", func(t *testing.T) {
+		fakeT := &testing.T{}
+		c.Check(fakeT, func(t *testing.T, objs struct {
+			ConfigMaps      []*corev1.ConfigMap `hull:"namespace=default"`
+			OtherConfigMaps []*corev1.ConfigMap `hull:"namespace=default"`
+		}) {
+		})
+		assert.True(t, fakeT.Failed())
+	})
+}
+
+func TestNewCheckerWithSources(t *testing.T) {
+	os, err := parser.Parse(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: webhook
+  namespace: cattle-system
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: webhook
+  namespace: cattle-system
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources := map[runtime.Object]Source{}
+	for _, obj := range os.All() {
+		if obj.GetObjectKind().GroupVersionKind().Kind == "ConfigMap" {
+			sources[obj] = Source{Template: "templates/webhook.yaml", Lines: LineRange{Start: 1, End: 6}}
+		}
+	}
+	c, err := NewCheckerWithSources(map[string]*objectset.ObjectSet{
+		"templates/webhook.yaml": os,
+	}, sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var configMapSource, serviceAccountSource, unknownSource Source
+	c.Check(t, NewCheckFunc(
+		NewChainedCheckFunc(func(tc *TestContext, objs struct {
+			ConfigMaps      []*corev1.ConfigMap
+			ServiceAccounts []*corev1.ServiceAccount
+		}) {
+			if assert.Len(t, objs.ConfigMaps, 1) {
+				configMapSource = tc.SourceOf(objs.ConfigMaps[0])
+			}
+			if assert.Len(t, objs.ServiceAccounts, 1) {
+				serviceAccountSource = tc.SourceOf(objs.ServiceAccounts[0])
+			}
+			unknownSource = tc.SourceOf(&corev1.ConfigMap{})
+		}),
+	))
+	assert.Equal(t, "templates/webhook.yaml:1-6", configMapSource.String())
+	assert.Equal(t, "templates/webhook.yaml", serviceAccountSource.String())
+	assert.Equal(t, Source{}, unknownSource)
+}
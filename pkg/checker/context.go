@@ -7,6 +7,8 @@ import (
 
 	"github.com/rancher/hull/pkg/extract"
 	helmChartUtil "helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 func NewContext() *TestContext {
@@ -23,12 +25,38 @@ type TestContext struct {
 	RenderValues helmChartUtil.Values
 
 	continueExecution bool
+
+	// objects are the rendered objects that the ChainedCheckFuncs are run against
+	objects []*unstructured.Unstructured
+	strict  *StrictDecoding
+
+	// deferred are run once the current ChainedCheckFunc returns
+	deferred []func()
+
+	// sources are the Sources of the objects, and decodedSources are the Sources of the objects that the current
+	// ChainedCheckFunc received that were decoded from them
+	sources        map[runtime.Object]Source
+	decodedSources map[runtime.Object]Source
 }
 
 func (tc *TestContext) Continue() {
 	tc.continueExecution = true
 }
 
+// Defer runs f once the current ChainedCheckFunc returns, before the chain checks whether it failed (i.e. to report
+// failures collected while it ran)
+func (tc *TestContext) Defer(f func()) {
+	tc.deferred = append(tc.deferred, f)
+}
+
+func (tc *TestContext) runDeferred() {
+	deferred := tc.deferred
+	tc.deferred = nil
+	for i := len(deferred) - 1; i >= 0; i-- {
+		deferred[i]()
+	}
+}
+
 func Store[K comparable, V interface{}](tc *TestContext, key K, value V) {
 	tc.Data[key] = value
 }
@@ -0,0 +1,228 @@
+package checker
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/rancher/wrangler/v3/pkg/objectset"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var simpleFieldRe = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Diff is a semantic diff between two sets of objects, where objects are matched by GVK, namespace, and name
+type Diff struct {
+	Added   []ObjectDiff
+	Removed []ObjectDiff
+	Changed []ObjectDiff
+}
+
+// ObjectDiff describes how a single object differs between two sets of objects
+//
+// Before is nil for added objects and After is nil for removed objects.
+type ObjectDiff struct {
+	GroupVersionKind schema.GroupVersionKind
+	Key              objectset.ObjectKey
+
+	Before *unstructured.Unstructured
+	After  *unstructured.Unstructured
+
+	Fields []FieldDiff
+}
+
+// FieldDiff describes a change to the field at Path (i.e. .spec.replicas or .metadata.labels["app.kubernetes.io/name"])
+//
+// Before is nil for added fields and After is nil for removed fields.
+type FieldDiff struct {
+	Path   string
+	Before interface{}
+	After  interface{}
+}
+
+func (d ObjectDiff) String() string {
+	return fmt.Sprintf("%s %s", d.GroupVersionKind, d.Key)
+}
+
+// Empty returns whether no objects were added, removed, or changed
+func (d *Diff) Empty() bool {
+	return d == nil || len(d.Added)+len(d.Removed)+len(d.Changed) == 0
+}
+
+// Strings summarizes the diff with one line per added object (+), removed object (-), or changed field (~)
+//
+// For example: "+ apps/v1, Kind=Deployment default/my-app" or "~ v1, Kind=ConfigMap default/my-config .data.hello".
+func (d *Diff) Strings() []string {
+	if d == nil {
+		return nil
+	}
+	var lines []string
+	for _, objDiff := range d.Added {
+		lines = append(lines, fmt.Sprintf("+ %s", objDiff))
+	}
+	for _, objDiff := range d.Removed {
+		lines = append(lines, fmt.Sprintf("- %s", objDiff))
+	}
+	for _, objDiff := range d.Changed {
+		for _, fieldDiff := range objDiff.Fields {
+			lines = append(lines, fmt.Sprintf("~ %s %s", objDiff, fieldDiff.Path))
+		}
+	}
+	return lines
+}
+
+// DiffObjectSets computes the Diff required to go from the objects in before to the objects in after
+func DiffObjectSets(before, after *objectset.ObjectSet) (*Diff, error) {
+	beforeObjs, err := toUnstructuredByGVK(before)
+	if err != nil {
+		return nil, err
+	}
+	afterObjs, err := toUnstructuredByGVK(after)
+	if err != nil {
+		return nil, err
+	}
+	diff := &Diff{}
+	for gvk, beforeByKey := range beforeObjs {
+		for key, beforeObj := range beforeByKey {
+			afterObj, ok := afterObjs[gvk][key]
+			if !ok {
+				diff.Removed = append(diff.Removed, ObjectDiff{
+					GroupVersionKind: gvk,
+					Key:              key,
+					Before:           beforeObj,
+				})
+				continue
+			}
+			fields := diffFields("", beforeObj.Object, afterObj.Object)
+			if len(fields) == 0 {
+				continue
+			}
+			diff.Changed = append(diff.Changed, ObjectDiff{
+				GroupVersionKind: gvk,
+				Key:              key,
+				Before:           beforeObj,
+				After:            afterObj,
+				Fields:           fields,
+			})
+		}
+	}
+	for gvk, afterByKey := range afterObjs {
+		for key, afterObj := range afterByKey {
+			if _, ok := beforeObjs[gvk][key]; ok {
+				continue
+			}
+			diff.Added = append(diff.Added, ObjectDiff{
+				GroupVersionKind: gvk,
+				Key:              key,
+				After:            afterObj,
+			})
+		}
+	}
+	for _, objDiffs := range [][]ObjectDiff{diff.Added, diff.Removed, diff.Changed} {
+		sort.Slice(objDiffs, func(i, j int) bool {
+			return objDiffs[i].String() < objDiffs[j].String()
+		})
+	}
+	return diff, nil
+}
+
+// ExpectDiff asserts that the diff's Strings exactly match the expected lines, in any order
+func ExpectDiff(t *testing.T, diff *Diff, expected []string) bool {
+	return assert.ElementsMatch(t, expected, diff.Strings(), "unexpected diff")
+}
+
+func toUnstructuredByGVK(os *objectset.ObjectSet) (map[schema.GroupVersionKind]map[objectset.ObjectKey]*unstructured.Unstructured, error) {
+	objs := make(map[schema.GroupVersionKind]map[objectset.ObjectKey]*unstructured.Unstructured)
+	if os == nil {
+		return objs, nil
+	}
+	for gvk, objsByKey := range os.ObjectsByGVK() {
+		objs[gvk] = make(map[objectset.ObjectKey]*unstructured.Unstructured)
+		for key, obj := range objsByKey {
+			u, ok := obj.(*unstructured.Unstructured)
+			if !ok {
+				uObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+				if err != nil {
+					return nil, fmt.Errorf("unable to convert %s %s to unstructured: %s", gvk, key, err)
+				}
+				u = &unstructured.Unstructured{Object: uObj}
+			}
+			objs[gvk][key] = u
+		}
+	}
+	return objs, nil
+}
+
+func diffFields(path string, before, after interface{}) []FieldDiff {
+	switch beforeVal := before.(type) {
+	case map[string]interface{}:
+		afterVal, ok := after.(map[string]interface{})
+		if !ok {
+			break
+		}
+		var fields []FieldDiff
+		for _, k := range sortedMapKeys(beforeVal, afterVal) {
+			fieldPath := joinFieldPath(path, k)
+			beforeField, inBefore := beforeVal[k]
+			afterField, inAfter := afterVal[k]
+			switch {
+			case !inBefore:
+				fields = append(fields, FieldDiff{Path: fieldPath, After: afterField})
+			case !inAfter:
+				fields = append(fields, FieldDiff{Path: fieldPath, Before: beforeField})
+			default:
+				fields = append(fields, diffFields(fieldPath, beforeField, afterField)...)
+			}
+		}
+		return fields
+	case []interface{}:
+		afterVal, ok := after.([]interface{})
+		if !ok {
+			break
+		}
+		var fields []FieldDiff
+		for i := 0; i < len(beforeVal) || i < len(afterVal); i++ {
+			fieldPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(beforeVal):
+				fields = append(fields, FieldDiff{Path: fieldPath, After: afterVal[i]})
+			case i >= len(afterVal):
+				fields = append(fields, FieldDiff{Path: fieldPath, Before: beforeVal[i]})
+			default:
+				fields = append(fields, diffFields(fieldPath, beforeVal[i], afterVal[i])...)
+			}
+		}
+		return fields
+	}
+	if reflect.DeepEqual(before, after) {
+		return nil
+	}
+	return []FieldDiff{{Path: path, Before: before, After: after}}
+}
+
+func joinFieldPath(path, field string) string {
+	if simpleFieldRe.MatchString(field) {
+		return path + "." + field
+	}
+	return fmt.Sprintf("%s[%q]", path, field)
+}
+
+func sortedMapKeys(maps ...map[string]interface{}) []string {
+	keySet := make(map[string]bool)
+	for _, m := range maps {
+		for k := range m {
+			keySet[k] = true
+		}
+	}
+	keys := make([]string, 0, len(keySet))
+	for k := range keySet {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,201 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/rancher/hull/pkg/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffObjectSets(t *testing.T) {
+	base := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: hello
+  namespace: world
+  labels:
+    app.kubernetes.io/name: hello
+data:
+  config: rancher
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hello
+  namespace: world
+spec:
+  template:
+    spec:
+      containers:
+      - name: hello
+        args:
+        - --debug
+`
+
+	testCases := []struct {
+		Name   string
+		Before string
+		After  string
+		Expect []string
+	}{
+		{
+			Name:   "No Changes",
+			Before: base,
+			After:  base,
+			Expect: nil,
+		},
+		{
+			Name:   "Nothing Before",
+			Before: "",
+			After: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: hello
+`,
+			Expect: []string{
+				"+ /v1, Kind=ConfigMap hello",
+			},
+		},
+		{
+			Name:   "Added And Removed Objects",
+			Before: base,
+			After: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: hello
+  namespace: world
+  labels:
+    app.kubernetes.io/name: hello
+data:
+  config: rancher
+---
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: hello
+  namespace: world
+`,
+			Expect: []string{
+				"+ monitoring.coreos.com/v1, Kind=ServiceMonitor world/hello",
+				"- apps/v1, Kind=Deployment world/hello",
+			},
+		},
+		{
+			Name:   "Changed Fields",
+			Before: base,
+			After: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: hello
+  namespace: world
+  labels:
+    app.kubernetes.io/name: world
+data:
+  config: rancher
+  extra: data
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hello
+  namespace: world
+spec:
+  template:
+    spec:
+      containers:
+      - name: hello
+        args:
+        - --trace
+        - --debug
+`,
+			Expect: []string{
+				`~ /v1, Kind=ConfigMap world/hello .metadata.labels["app.kubernetes.io/name"]`,
+				"~ /v1, Kind=ConfigMap world/hello .data.extra",
+				"~ apps/v1, Kind=Deployment world/hello .spec.template.spec.containers[0].args[0]",
+				"~ apps/v1, Kind=Deployment world/hello .spec.template.spec.containers[0].args[1]",
+			},
+		},
+		{
+			Name:   "Changed Type",
+			Before: base,
+			After: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: hello
+  namespace: world
+  labels:
+    app.kubernetes.io/name: hello
+data: {}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hello
+  namespace: world
+spec:
+  template: null
+`,
+			Expect: []string{
+				"~ /v1, Kind=ConfigMap world/hello .data.config",
+				"~ apps/v1, Kind=Deployment world/hello .spec.template",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			before, err := parser.Parse(tc.Before)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			after, err := parser.Parse(tc.After)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			diff, err := DiffObjectSets(before, after)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, len(tc.Expect) == 0, diff.Empty())
+			ExpectDiff(t, diff, tc.Expect)
+		})
+	}
+
+	t.Run("Field Values", func(t *testing.T) {
+		before, _ := parser.Parse(base)
+		after, _ := parser.Parse(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: hello
+  namespace: world
+  labels:
+    app.kubernetes.io/name: hello
+data:
+  config: hull
+`)
+		diff, err := DiffObjectSets(before, after)
+		if !assert.NoError(t, err) {
+			return
+		}
+		if !assert.Len(t, diff.Changed, 1) || !assert.Len(t, diff.Changed[0].Fields, 1) {
+			return
+		}
+		assert.Equal(t, FieldDiff{Path: ".data.config", Before: "rancher", After: "hull"}, diff.Changed[0].Fields[0])
+		assert.NotNil(t, diff.Changed[0].Before)
+		assert.NotNil(t, diff.Changed[0].After)
+	})
+
+	t.Run("Nil ObjectSets", func(t *testing.T) {
+		diff, err := DiffObjectSets(nil, nil)
+		assert.NoError(t, err)
+		assert.True(t, diff.Empty())
+	})
+}
@@ -0,0 +1,245 @@
+package expect
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/rancher/hull/pkg/checker"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Object collects expectations about the fields of an object
+//
+// Failed expectations are not reported immediately; every failed expectation of a TestContext is reported in a single
+// error once the ChainedCheckFunc that made them returns, grouped by object.
+type Object struct {
+	report *report
+
+	description string
+	source      checker.Source
+	data        interface{}
+	err         error
+}
+
+// Field is a field of an object, identified by a JSONPath expression
+type Field struct {
+	object *Object
+
+	path   string
+	values []interface{}
+	err    error
+}
+
+type report struct {
+	objects  []*Object
+	failures map[*Object][]string
+}
+
+type reportKey struct{}
+
+// That returns an Object to set expectations on the fields of obj, which must be a rendered object or an object that
+// the current ChainedCheckFunc received
+func That(tc *checker.TestContext, obj runtime.Object) *Object {
+	o := &Object{
+		report:      reportOf(tc),
+		description: describe(obj),
+		source:      tc.SourceOf(obj),
+	}
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		o.data = u.Object
+	} else {
+		o.data, o.err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	}
+	return o
+}
+
+// Field returns the field at the JSONPath expression, i.e. spec.template.spec.containers[*].image; the leading . and
+// the enclosing braces are optional
+func (o *Object) Field(path string) *Field {
+	path = strings.TrimPrefix(strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}"), ".")
+	f := &Field{
+		object: o,
+		path:   "." + path,
+	}
+	if o.err != nil {
+		f.err = o.err
+		return f
+	}
+	f.values, f.err = find(o.data, f.path)
+	return f
+}
+
+// Exists expects the field to be set
+func (f *Field) Exists() *Object {
+	if f.err != nil {
+		f.fail("expected field to exist: %s", f.err)
+	}
+	return f.object
+}
+
+// DoesNotExist expects the field to not be set
+func (f *Field) DoesNotExist() *Object {
+	if f.err == nil {
+		f.fail("expected field to not exist, found %s", f.value())
+	}
+	return f.object
+}
+
+// Equals expects the field to be equal to the value once both are encoded as JSON; a JSONPath expression that matches
+// several fields (i.e. containers[*].name) is compared to a slice of their values
+func (f *Field) Equals(value interface{}) *Object {
+	if f.err != nil {
+		f.fail("expected %s: %s", checker.ToJSON(value), f.err)
+		return f.object
+	}
+	if !jsonEqual(value, f.get()) {
+		f.fail("expected %s, found %s", checker.ToJSON(value), f.value())
+	}
+	return f.object
+}
+
+// Matches expects every field that the JSONPath expression matches to be a string that matches the regular expression
+func (f *Field) Matches(expr string) *Object {
+	if f.err != nil {
+		f.fail("expected match for %s: %s", expr, f.err)
+		return f.object
+	}
+	r, err := regexp.Compile(expr)
+	if err != nil {
+		f.fail("invalid regular expression %s: %s", expr, err)
+		return f.object
+	}
+	for _, value := range f.values {
+		str, ok := value.(string)
+		if !ok || !r.MatchString(str) {
+			f.fail("expected match for %s, found %s", expr, checker.ToJSON(value))
+		}
+	}
+	return f.object
+}
+
+func (f *Field) get() interface{} {
+	if len(f.values) == 1 {
+		return f.values[0]
+	}
+	return f.values
+}
+
+func (f *Field) value() string {
+	return checker.ToJSON(f.get())
+}
+
+func (f *Field) fail(format string, args ...interface{}) {
+	message := fmt.Sprintf("%s: %s", f.path, fmt.Sprintf(format, args...))
+	if len(f.object.source.Template) > 0 {
+		message = fmt.Sprintf("%s (%s): %s", f.path, f.object.source.Field(f.path), fmt.Sprintf(format, args...))
+	}
+	f.object.report.add(f.object, message)
+}
+
+// reportOf returns the report of the failed expectations of the current ChainedCheckFunc, which is reported once it
+// returns
+func reportOf(tc *checker.TestContext) *report {
+	if r, ok := checker.Get[reportKey, *report](tc, reportKey{}); ok && r != nil {
+		return r
+	}
+	r := &report{
+		failures: make(map[*Object][]string),
+	}
+	checker.Store(tc, reportKey{}, r)
+	tc.Defer(func() {
+		checker.Store[reportKey, *report](tc, reportKey{}, nil)
+		if message := r.String(); len(message) > 0 {
+			tc.T.Error(message)
+		}
+	})
+	return r
+}
+
+func (r *report) add(o *Object, message string) {
+	if _, ok := r.failures[o]; !ok {
+		r.objects = append(r.objects, o)
+	}
+	r.failures[o] = append(r.failures[o], message)
+}
+
+func (r *report) String() string {
+	var count int
+	var b strings.Builder
+	for _, o := range r.objects {
+		count += len(r.failures[o])
+		b.WriteString("\n" + o.description)
+		if len(o.source.Template) > 0 {
+			b.WriteString(fmt.Sprintf(" (%s)", o.source))
+		}
+		b.WriteString(":")
+		for _, failure := range r.failures[o] {
+			b.WriteString("\n  - " + failure)
+		}
+	}
+	if count == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d expectation(s) failed:%s", count, b.String())
+}
+
+// describe returns the group, version, kind, and key of the object
+func describe(obj runtime.Object) string {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if gvk.Empty() {
+		if gvks, _, err := checker.Scheme.ObjectKinds(obj); err == nil && len(gvks) > 0 {
+			gvk = gvks[0]
+		}
+	}
+	kind := gvk.Kind
+	if len(kind) == 0 {
+		kind = reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	}
+	description := kind
+	if apiVersion := gvk.GroupVersion().String(); len(apiVersion) > 0 && len(gvk.Version) > 0 {
+		description = fmt.Sprintf("%s %s", apiVersion, kind)
+	}
+	metaObj, err := meta.Accessor(obj)
+	if err != nil {
+		return description
+	}
+	return fmt.Sprintf("%s %s", description, checker.Key(metaObj))
+}
+
+func find(data interface{}, path string) ([]interface{}, error) {
+	j := jsonpath.New("expect")
+	if err := j.Parse(fmt.Sprintf("{%s}", path)); err != nil {
+		return nil, fmt.Errorf("invalid JSONPath expression: %s", err)
+	}
+	results, err := j.FindResults(data)
+	if err != nil {
+		return nil, err
+	}
+	var values []interface{}
+	for _, result := range results {
+		for _, value := range result {
+			values = append(values, value.Interface())
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%s is not found", path)
+	}
+	return values, nil
+}
+
+func jsonEqual(expected, actual interface{}) bool {
+	var expectedValue, actualValue interface{}
+	if err := json.Unmarshal([]byte(checker.ToJSON(expected)), &expectedValue); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(checker.ToJSON(actual)), &actualValue); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(expectedValue, actualValue)
+}
@@ -0,0 +1,148 @@
+package expect
+
+import (
+	"testing"
+
+	"github.com/rancher/hull/pkg/checker"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+const manifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hello
+  namespace: world
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: hello
+  template:
+    metadata:
+      labels:
+        app: hello
+    spec:
+      securityContext:
+        runAsNonRoot: true
+      containers:
+      - name: hello
+        image: rancher/hello:v0.0.0
+      - name: world
+        image: rancher/world:v0.0.0
+`
+
+func TestExpect(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Expect     func(tc *checker.TestContext, obj *appsv1.Deployment)
+		ShouldFail bool
+	}{
+		{
+			Name: "Equals",
+			Expect: func(tc *checker.TestContext, obj *appsv1.Deployment) {
+				That(tc, obj).
+					Field("spec.template.spec.securityContext.runAsNonRoot").Equals(true).
+					Field(".spec.replicas").Equals(2).
+					Field("{.spec.selector.matchLabels}").Equals(map[string]string{"app": "hello"})
+			},
+		},
+		{
+			Name: "Equals Several Fields",
+			Expect: func(tc *checker.TestContext, obj *appsv1.Deployment) {
+				That(tc, obj).Field("spec.template.spec.containers[*].name").Equals([]string{"hello", "world"})
+			},
+		},
+		{
+			Name: "Not Equals",
+			Expect: func(tc *checker.TestContext, obj *appsv1.Deployment) {
+				That(tc, obj).Field("spec.replicas").Equals(3)
+			},
+			ShouldFail: true,
+		},
+		{
+			Name: "Equals Missing Field",
+			Expect: func(tc *checker.TestContext, obj *appsv1.Deployment) {
+				That(tc, obj).Field("spec.template.spec.hostNetwork").Equals(false)
+			},
+			ShouldFail: true,
+		},
+		{
+			Name: "Exists",
+			Expect: func(tc *checker.TestContext, obj *appsv1.Deployment) {
+				That(tc, obj).Field("spec.template.spec.containers[0].image").Exists()
+			},
+		},
+		{
+			Name: "Does Not Exist",
+			Expect: func(tc *checker.TestContext, obj *appsv1.Deployment) {
+				That(tc, obj).Field("spec.template.spec.containers[2]").DoesNotExist()
+			},
+		},
+		{
+			Name: "Unexpectedly Exists",
+			Expect: func(tc *checker.TestContext, obj *appsv1.Deployment) {
+				That(tc, obj).Field("spec.replicas").DoesNotExist()
+			},
+			ShouldFail: true,
+		},
+		{
+			Name: "Matches",
+			Expect: func(tc *checker.TestContext, obj *appsv1.Deployment) {
+				That(tc, obj).Field("spec.template.spec.containers[*].image").Matches(`^rancher/`)
+			},
+		},
+		{
+			Name: "Does Not Match",
+			Expect: func(tc *checker.TestContext, obj *appsv1.Deployment) {
+				That(tc, obj).Field("spec.template.spec.containers[*].image").Matches(`^rancher/hello:`)
+			},
+			ShouldFail: true,
+		},
+		{
+			Name: "Invalid Path",
+			Expect: func(tc *checker.TestContext, obj *appsv1.Deployment) {
+				That(tc, obj).Field("spec.template.spec.containers[").Exists()
+			},
+			ShouldFail: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			c, err := checker.NewCheckerFromString(manifest, "manifest.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			fakeT := &testing.T{}
+			c.Check(fakeT, checker.NewCheckFunc(
+				checker.PerResource(tc.Expect),
+			))
+			assert.Equal(t, tc.ShouldFail, fakeT.Failed())
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	c, err := checker.NewCheckerFromString(manifest, "manifest.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var message string
+	c.Check(&testing.T{}, checker.NewCheckFunc(
+		checker.NewChainedCheckFunc(func(tc *checker.TestContext, objs struct{ Deployments []*appsv1.Deployment }) {
+			for _, obj := range objs.Deployments {
+				That(tc, obj).
+					Field("spec.replicas").Equals(3).
+					Field("spec.template.spec.securityContext.runAsNonRoot").Equals(true).
+					Field("spec.template.spec.hostNetwork").Exists()
+			}
+			message = reportOf(tc).String()
+		}),
+	))
+	assert.Equal(t, `2 expectation(s) failed:
+apps/v1 Deployment {world hello} (manifest.yaml):
+  - .spec.replicas (manifest.yaml): expected 3, found 2
+  - .spec.template.spec.hostNetwork (manifest.yaml): expected field to exist: hostNetwork is not found`, message)
+}
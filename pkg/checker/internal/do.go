@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
@@ -21,6 +22,16 @@ type DoFunc func(t *testing.T, objs []runtime.Object)
 type ParseOptions struct {
 	Scheme *runtime.Scheme
 	Strict bool
+
+	// OnParse, if provided, is called with every object that is set on a field of the struct
+	OnParse func(obj runtime.Object)
+
+	// SourceOf, if provided, returns the path of the template that the object was rendered from
+	SourceOf func(obj runtime.Object) string
+
+	// OnDecode, if provided, is called with every object that is set on a field of the struct after being decoded from
+	// the original object
+	OnDecode func(original, obj runtime.Object)
 }
 
 func (o *ParseOptions) setDefaults() *ParseOptions {
@@ -113,52 +124,165 @@ func parseObjectsIntoStruct(objs []runtime.Object, objectStruct interface{}, opt
 		return nil
 	}
 
-	for _, obj := range objs {
+	for _, original := range objs {
 		// Identify object type from scheme
-		var objType reflect.Type
-		gvk := obj.GetObjectKind().GroupVersionKind()
-		for kind, reflectType := range opts.Scheme.KnownTypes(gvk.GroupVersion()) {
-			if kind == gvk.Kind {
-				objType = reflect.PtrTo(reflectType)
-				newObj := reflect.New(objType.Elem()).Interface()
-				err := opts.Scheme.Convert(obj, newObj, nil)
-				if err != nil {
-					continue
-				}
-				obj = newObj.(runtime.Object)
-				obj.GetObjectKind().SetGroupVersionKind(gvk)
-				break
+		obj, decoded := decode(opts.Scheme, original)
+		objType := reflect.TypeOf(obj)
+		if decoded && opts.Strict {
+			lostFields, err := LostFields(opts.Scheme, original)
+			if err != nil {
+				return err
+			}
+			if len(lostFields) > 0 {
+				return fmt.Errorf("could not unmarshall object of type %s without dropping fields %s", objType, strings.Join(lostFields, ", "))
 			}
 		}
-		if objType == nil {
-			// fall back to provided type
-			objType = reflect.TypeOf(obj)
+		var source string
+		if opts.SourceOf != nil {
+			source = opts.SourceOf(original)
 		}
-		fieldPath, exists := supportedTypes.getFieldPath(objType)
-		if !exists {
-			unstructuredFieldPath, exists := supportedTypes.getFieldPath(unstructuredType)
-			if !exists {
+		fieldPaths := supportedTypes.getFieldPaths(objType, obj, source)
+		if len(fieldPaths) == 0 {
+			fieldPaths = supportedTypes.getFieldPaths(unstructuredType, obj, source)
+			if len(fieldPaths) == 0 {
 				if !opts.Strict {
 					continue
 				}
-				return fmt.Errorf("could not unmarshall object of type %s into %s since it was not identified as a supported type %s: %s", reflect.TypeOf(obj), objectStructType, supportedTypes, err)
+				return fmt.Errorf("could not unmarshall object of type %s into %s since it was not identified as a supported type %s", objType, objectStructType, supportedTypes)
 			}
-			fieldPath = unstructuredFieldPath
-			uObj, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
-			obj = &unstructured.Unstructured{
-				Object: uObj,
+			if uObj, ok := original.(*unstructured.Unstructured); ok {
+				// pass along the object as it was provided rather than as it was decoded
+				obj = uObj
+			} else {
+				uObj, _ := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+				obj = &unstructured.Unstructured{
+					Object: uObj,
+				}
+			}
+		}
+		for _, fieldPath := range fieldPaths {
+			fieldNames := strings.Split(fieldPath, ".")
+			fieldVal := reflect.ValueOf(objectStruct).Elem()
+			for _, fieldName := range fieldNames {
+				fieldVal = fieldVal.FieldByName(fieldName)
 			}
+			appendToField(fieldVal, obj, source)
 		}
-		fieldNames := strings.Split(fieldPath, ".")
-		fieldVal := reflect.ValueOf(objectStruct).Elem()
-		for _, fieldName := range fieldNames {
-			fieldVal = fieldVal.FieldByName(fieldName)
+		if opts.OnParse != nil {
+			opts.OnParse(original)
+		}
+		if opts.OnDecode != nil && obj != original {
+			opts.OnDecode(original, obj)
 		}
-		fieldVal.Set(reflect.Append(fieldVal, reflect.ValueOf(obj)))
 	}
 	return nil
 }
 
+// appendToField appends the object to the slice, or to the slice keyed by the source in the map, held by the field
+func appendToField(fieldVal reflect.Value, obj runtime.Object, source string) {
+	if fieldVal.Kind() != reflect.Map {
+		fieldVal.Set(reflect.Append(fieldVal, reflect.ValueOf(obj)))
+		return
+	}
+	if fieldVal.IsNil() {
+		fieldVal.Set(reflect.MakeMap(fieldVal.Type()))
+	}
+	key := reflect.ValueOf(source).Convert(fieldVal.Type().Key())
+	objs := fieldVal.MapIndex(key)
+	if !objs.IsValid() {
+		objs = reflect.Zero(fieldVal.Type().Elem())
+	}
+	fieldVal.SetMapIndex(key, reflect.Append(objs, reflect.ValueOf(obj)))
+}
+
+// decode converts the object into the Go type registered for its GroupVersionKind in the scheme, if any
+func decode(scheme *runtime.Scheme, obj runtime.Object) (runtime.Object, bool) {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	for kind, reflectType := range scheme.KnownTypes(gvk.GroupVersion()) {
+		if kind != gvk.Kind {
+			continue
+		}
+		newObj := reflect.New(reflectType).Interface()
+		if err := scheme.Convert(obj, newObj, nil); err != nil {
+			continue
+		}
+		typedObj := newObj.(runtime.Object)
+		typedObj.GetObjectKind().SetGroupVersionKind(gvk)
+		return typedObj, true
+	}
+	return obj, false
+}
+
+// LostFields returns the paths of the non-empty fields of the object that are dropped when it is decoded into the Go
+// type registered for it in the scheme, i.e. fields that are misspelled or set at the wrong level
+func LostFields(scheme *runtime.Scheme, obj runtime.Object) ([]string, error) {
+	typedObj, decoded := decode(scheme, obj)
+	if !decoded {
+		return nil, nil
+	}
+	original, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	roundTripped, err := runtime.DefaultUnstructuredConverter.ToUnstructured(typedObj)
+	if err != nil {
+		return nil, err
+	}
+	return lostFields(original, roundTripped, ""), nil
+}
+
+func lostFields(original, roundTripped interface{}, path string) []string {
+	var lost []string
+	switch o := original.(type) {
+	case map[string]interface{}:
+		r, _ := roundTripped.(map[string]interface{})
+		keys := make([]string, 0, len(o))
+		for key := range o {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if isEmpty(o[key]) {
+				continue
+			}
+			keyPath := key
+			if len(path) > 0 {
+				keyPath = path + "." + key
+			}
+			value, ok := r[key]
+			if !ok {
+				lost = append(lost, keyPath)
+				continue
+			}
+			lost = append(lost, lostFields(o[key], value, keyPath)...)
+		}
+	case []interface{}:
+		r, _ := roundTripped.([]interface{})
+		for i := range o {
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+			if i >= len(r) {
+				lost = append(lost, elemPath)
+				continue
+			}
+			lost = append(lost, lostFields(o[i], r[i], elemPath)...)
+		}
+	}
+	return lost
+}
+
+// isEmpty returns whether the value would be dropped by a field tagged with omitempty
+func isEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
 func getSupportedTypes(objStructType reflect.Type) (*fieldTypeTracker, error) {
 	supportedTypes := newFieldTypeTracker()
 	return supportedTypes, addSupportedTypes(supportedTypes, objStructType, "")
@@ -191,6 +315,13 @@ func addSupportedTypes(supportedTypes *fieldTypeTracker, objStructType reflect.T
 			}
 			continue
 		}
+		// Check if field is a map of template paths to slices
+		if fieldType.Kind() == reflect.Map {
+			if fieldType.Key().Kind() != reflect.String {
+				return fmt.Errorf("field %s must be a map keyed by the path of a template", field.Name)
+			}
+			fieldType = fieldType.Elem()
+		}
 		// Check if field is a slice
 		if fieldType.Kind() != reflect.Slice {
 			return fmt.Errorf("field %s must be a slice of structs that implement v1.Object and runtime.Object", field.Name)
@@ -203,6 +334,16 @@ func addSupportedTypes(supportedTypes *fieldTypeTracker, objStructType reflect.T
 		if !fieldElemType.Implements(objectInterface) {
 			return fmt.Errorf("field %s contains object(s) of type %s that do not implement %s", field.Name, fieldElemType, objectInterface)
 		}
+		if tag := field.Tag.Get(selectorTag); len(tag) > 0 {
+			selector, err := parseFieldSelector(tag)
+			if err != nil {
+				return fmt.Errorf("field %s has invalid %s tag: %s", field.Name, selectorTag, err)
+			}
+			if err := supportedTypes.addSelectedType(fieldPath, fieldElemType, selector); err != nil {
+				return err
+			}
+			continue
+		}
 		err := supportedTypes.addType(fieldPath, fieldElemType)
 		if err != nil {
 			return err
@@ -831,3 +831,90 @@ func TestGetSupportedTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestLostFields(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Object   map[string]interface{}
+		Expected []string
+	}{
+		{
+			Name: "No Lost Fields",
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name":        "hello",
+					"namespace":   "world",
+					"annotations": map[string]interface{}{},
+				},
+				"data": map[string]interface{}{
+					"hello": "world",
+				},
+			},
+		},
+		{
+			Name: "Empty Unknown Fields",
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name": "hello",
+				},
+				"dta": map[string]interface{}{},
+			},
+		},
+		{
+			Name: "Unknown Fields",
+			Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name": "hello",
+				},
+				"spec": map[string]interface{}{
+					"replica": int64(2),
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{
+									"name":  "hello",
+									"image": "world",
+								},
+								map[string]interface{}{
+									"name":            "world",
+									"imagePullPolicy": "Always",
+									"imagePullSecret": "hello",
+								},
+							},
+						},
+					},
+				},
+			},
+			Expected: []string{"spec.replica", "spec.template.spec.containers[1].imagePullSecret"},
+		},
+		{
+			Name: "Unknown Type",
+			Object: map[string]interface{}{
+				"apiVersion": "hello.cattle.io/v1",
+				"kind":       "World",
+				"metadata": map[string]interface{}{
+					"name": "hello",
+				},
+				"spec": map[string]interface{}{
+					"hello": "world",
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			lostFields, err := LostFields(defaultScheme, &unstructured.Unstructured{Object: tc.Object})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.Expected, lostFields)
+		})
+	}
+}
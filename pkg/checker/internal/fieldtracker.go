@@ -3,11 +3,22 @@ package internal
 import (
 	"fmt"
 	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 type fieldTypeTracker struct {
 	typeToField map[reflect.Type]string
 	fieldToType map[string]reflect.Type
+
+	// selectedFields are the fields that only track objects of their type that match a selector
+	selectedFields []selectedField
+}
+
+type selectedField struct {
+	name      string
+	fieldType reflect.Type
+	selector  *fieldSelector
 }
 
 func newFieldTypeTracker() *fieldTypeTracker {
@@ -29,6 +40,42 @@ func (r *fieldTypeTracker) addType(fieldName string, fieldType reflect.Type) err
 	return nil
 }
 
+func (r *fieldTypeTracker) addSelectedType(fieldName string, fieldType reflect.Type, selector *fieldSelector) error {
+	if currType, exists := r.fieldToType[fieldName]; exists && currType != fieldType {
+		return fmt.Errorf("field %s is already tracking %s, cannot also track %s", fieldName, currType, fieldType)
+	}
+	for _, field := range r.selectedFields {
+		if field.name == fieldName {
+			return nil
+		}
+		if field.fieldType == fieldType && field.selector.tag == selector.tag {
+			return fmt.Errorf("field %s and %s track the same object type %s with the same selector %q", field.name, fieldName, fieldType, selector.tag)
+		}
+	}
+	r.selectedFields = append(r.selectedFields, selectedField{
+		name:      fieldName,
+		fieldType: fieldType,
+		selector:  selector,
+	})
+	r.fieldToType[fieldName] = fieldType
+	return nil
+}
+
+// getFieldPaths returns the paths of every field that tracks the object, which was rendered from the template at the
+// source path
+func (r *fieldTypeTracker) getFieldPaths(fieldType reflect.Type, obj runtime.Object, source string) []string {
+	var fieldPaths []string
+	if fieldPath, exists := r.getFieldPath(fieldType); exists {
+		fieldPaths = append(fieldPaths, fieldPath)
+	}
+	for _, field := range r.selectedFields {
+		if field.fieldType == fieldType && field.selector.matches(obj, source) {
+			fieldPaths = append(fieldPaths, field.name)
+		}
+	}
+	return fieldPaths
+}
+
 func (r *fieldTypeTracker) getFieldPath(fieldType reflect.Type) (string, bool) {
 	for supportedType, field := range r.typeToField {
 		if fieldType == supportedType {
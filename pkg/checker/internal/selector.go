@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// selectorTag is the struct tag that filters the objects that are set on a field, i.e.
+// `hull:"namespace=cattle-system,label=app=foo,template=templates/rbac.yaml"` or `hull:"name=~^webhook-"`
+//
+// Each filter is a key=value pair; the value of namespace, name, and template filters is matched exactly or, if
+// prefixed by ~, as a regular expression, and the value of label filters is a label selector. An object must match
+// every filter to be set on the field.
+const selectorTag = "hull"
+
+type fieldSelector struct {
+	tag string
+
+	namespace *stringMatcher
+	name      *stringMatcher
+	template  *stringMatcher
+	labels    []labels.Selector
+}
+
+type stringMatcher struct {
+	value  string
+	regexp *regexp.Regexp
+}
+
+func parseFieldSelector(tag string) (*fieldSelector, error) {
+	selector := &fieldSelector{
+		tag: tag,
+	}
+	for _, filter := range strings.Split(tag, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(filter), "=")
+		if !ok || len(value) == 0 {
+			return nil, fmt.Errorf("filter %q must be of the form key=value", filter)
+		}
+		var err error
+		switch key {
+		case "namespace":
+			selector.namespace, err = parseStringMatcher(value)
+		case "name":
+			selector.name, err = parseStringMatcher(value)
+		case "template":
+			selector.template, err = parseStringMatcher(value)
+		case "label":
+			var labelSelector labels.Selector
+			labelSelector, err = labels.Parse(value)
+			selector.labels = append(selector.labels, labelSelector)
+		default:
+			return nil, fmt.Errorf("filter %q has unknown key %s, expected one of namespace, name, label, or template", filter, key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("filter %q is invalid: %s", filter, err)
+		}
+	}
+	return selector, nil
+}
+
+func parseStringMatcher(value string) (*stringMatcher, error) {
+	if !strings.HasPrefix(value, "~") {
+		return &stringMatcher{value: value}, nil
+	}
+	r, err := regexp.Compile(strings.TrimPrefix(value, "~"))
+	if err != nil {
+		return nil, err
+	}
+	return &stringMatcher{regexp: r}, nil
+}
+
+func (m *stringMatcher) matches(value string) bool {
+	if m == nil {
+		return true
+	}
+	if m.regexp != nil {
+		return m.regexp.MatchString(value)
+	}
+	return m.value == value
+}
+
+// matches returns whether the object, which was rendered from the template at the source path, passes every filter
+func (s *fieldSelector) matches(obj runtime.Object, source string) bool {
+	metaObj, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	if !s.namespace.matches(metaObj.GetNamespace()) || !s.name.matches(metaObj.GetName()) || !s.template.matches(source) {
+		return false
+	}
+	for _, labelSelector := range s.labels {
+		if !labelSelector.Matches(labels.Set(metaObj.GetLabels())) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestFieldSelector(t *testing.T) {
+	webhookConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "webhook-config",
+			Namespace: "cattle-system",
+			Labels: map[string]string{
+				"app":       "webhook",
+				"component": "config",
+			},
+		},
+	}
+
+	testCases := []struct {
+		Name             string
+		Tag              string
+		Object           runtime.Object
+		Source           string
+		ShouldMatch      bool
+		ShouldThrowError bool
+	}{
+		{
+			Name:        "Namespace",
+			Tag:         "namespace=cattle-system",
+			Object:      webhookConfigMap,
+			ShouldMatch: true,
+		},
+		{
+			Name:   "Other Namespace",
+			Tag:    "namespace=default",
+			Object: webhookConfigMap,
+		},
+		{
+			Name:        "Name Regex",
+			Tag:         "name=~^webhook-",
+			Object:      webhookConfigMap,
+			ShouldMatch: true,
+		},
+		{
+			Name:   "Name Is Not A Prefix",
+			Tag:    "name=webhook-",
+			Object: webhookConfigMap,
+		},
+		{
+			Name:        "Labels",
+			Tag:         "label=app=webhook,label=component",
+			Object:      webhookConfigMap,
+			ShouldMatch: true,
+		},
+		{
+			Name:   "Missing Label",
+			Tag:    "label=app=webhook,label=component!=config",
+			Object: webhookConfigMap,
+		},
+		{
+			Name:        "Template",
+			Tag:         "namespace=cattle-system,template=templates/webhook.yaml",
+			Object:      webhookConfigMap,
+			Source:      "templates/webhook.yaml",
+			ShouldMatch: true,
+		},
+		{
+			Name:   "Other Template",
+			Tag:    "template=templates/webhook.yaml",
+			Object: webhookConfigMap,
+			Source: "templates/rbac.yaml",
+		},
+		{
+			Name:        "Template Regex",
+			Tag:         `template=~^templates/webhook/.*\.yaml$`,
+			Object:      webhookConfigMap,
+			Source:      "templates/webhook/configmap.yaml",
+			ShouldMatch: true,
+		},
+		{
+			Name:             "Unknown Key",
+			Tag:              "kind=ConfigMap",
+			ShouldThrowError: true,
+		},
+		{
+			Name:             "Missing Value",
+			Tag:              "namespace",
+			ShouldThrowError: true,
+		},
+		{
+			Name:             "Invalid Regex",
+			Tag:              "name=~(",
+			ShouldThrowError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			selector, err := parseFieldSelector(tc.Tag)
+			if tc.ShouldThrowError {
+				assert.Error(t, err)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.ShouldMatch, selector.matches(tc.Object, tc.Source))
+		})
+	}
+}
@@ -34,37 +34,54 @@ func PerResource[O metav1.Object](typedCheckFunc func(tc *TestContext, object O)
 	})
 }
 
+// workloadObjects are the objects whose pod templates are checked by OnWorkloads
+type workloadObjects struct {
+	Deployments  []*appsv1.Deployment
+	DaemonSets   []*appsv1.DaemonSet
+	StatefulSets []*appsv1.StatefulSet
+	ReplicaSets  []*appsv1.ReplicaSet
+	Jobs         []*batchv1.Job
+	CronJobs     []*batchv1.CronJob
+}
+
+func (w workloadObjects) podTemplateSpecs() map[metav1.Object]corev1.PodTemplateSpec {
+	podTemplateSpecs := make(map[metav1.Object]corev1.PodTemplateSpec)
+	for _, obj := range w.Deployments {
+		podTemplateSpecs[obj] = obj.Spec.Template
+	}
+	for _, obj := range w.DaemonSets {
+		podTemplateSpecs[obj] = obj.Spec.Template
+	}
+	for _, obj := range w.StatefulSets {
+		podTemplateSpecs[obj] = obj.Spec.Template
+	}
+	for _, obj := range w.ReplicaSets {
+		podTemplateSpecs[obj] = obj.Spec.Template
+	}
+	for _, obj := range w.Jobs {
+		podTemplateSpecs[obj] = obj.Spec.Template
+	}
+	for _, obj := range w.CronJobs {
+		podTemplateSpecs[obj] = obj.Spec.JobTemplate.Spec.Template
+	}
+	return podTemplateSpecs
+}
+
+// podTemplateSpecField returns the path to the pod template of a workload
+func podTemplateSpecField(obj metav1.Object) string {
+	if _, ok := obj.(*batchv1.CronJob); ok {
+		return "spec.jobTemplate.spec.template"
+	}
+	return "spec.template"
+}
+
 func OnWorkloads(typedCheckFunc func(tc *TestContext, podTemplateSpecs map[metav1.Object]corev1.PodTemplateSpec)) ChainedCheckFunc {
 	return func(tc *TestContext) CheckFunc {
 		return func(t *testing.T, objs struct {
-			Deployments []*appsv1.Deployment
-			DaemonSet   []*appsv1.DaemonSet
-			StatefulSet []*appsv1.StatefulSet
-			ReplicaSet  []*appsv1.ReplicaSet
-			Jobs        []*batchv1.Job
-			CronJobs    []*batchv1.CronJob
+			Workloads workloadObjects
 		}) {
 			tc.T = t
-			podTemplateSpecs := make(map[metav1.Object]corev1.PodTemplateSpec)
-			for _, obj := range objs.Deployments {
-				podTemplateSpecs[obj] = obj.Spec.Template
-			}
-			for _, obj := range objs.DaemonSet {
-				podTemplateSpecs[obj] = obj.Spec.Template
-			}
-			for _, obj := range objs.StatefulSet {
-				podTemplateSpecs[obj] = obj.Spec.Template
-			}
-			for _, obj := range objs.ReplicaSet {
-				podTemplateSpecs[obj] = obj.Spec.Template
-			}
-			for _, obj := range objs.Jobs {
-				podTemplateSpecs[obj] = obj.Spec.Template
-			}
-			for _, obj := range objs.CronJobs {
-				podTemplateSpecs[obj] = obj.Spec.JobTemplate.Spec.Template
-			}
-			typedCheckFunc(tc, podTemplateSpecs)
+			typedCheckFunc(tc, objs.Workloads.podTemplateSpecs())
 		}
 	}
 }
@@ -0,0 +1,286 @@
+package checker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+)
+
+// Reference is a reference by name from a rendered object to another object
+type Reference struct {
+	// From is the object that holds the reference
+	From metav1.Object
+	// Field is the path to the field of From that holds the reference
+	Field string
+	// Kind is the kind of the referenced object
+	Kind string
+	// Key is the namespace and name of the referenced object; the namespace is empty for cluster-scoped objects
+	Key relatedresource.Key
+	// Resolved is whether the referenced object is rendered by the chart
+	Resolved bool
+}
+
+func (r Reference) String() string {
+	return fmt.Sprintf("%T %s references %s %s at %s", r.From, Key(r.From), r.Kind, r.Key, r.Field)
+}
+
+// ReferenceOptions configures References
+type ReferenceOptions struct {
+	// Allow are the objects, keyed by kind, that are expected to exist before the chart is installed; a key without a
+	// namespace allows the name in every namespace
+	//
+	// The default ServiceAccount and kube-root-ca.crt ConfigMap of every namespace and the default ClusterRoles
+	// (cluster-admin, admin, edit, view, and system:*) are always allowed.
+	Allow map[string][]relatedresource.Key
+}
+
+func (o *ReferenceOptions) allows(r Reference) bool {
+	switch {
+	case r.Kind == "ServiceAccount" && r.Key.Name == "default":
+		return true
+	case r.Kind == "ConfigMap" && r.Key.Name == "kube-root-ca.crt":
+		return true
+	case r.Kind == "ClusterRole" && (strings.HasPrefix(r.Key.Name, "system:") || r.Key.Name == "cluster-admin" || r.Key.Name == "admin" || r.Key.Name == "edit" || r.Key.Name == "view"):
+		return true
+	}
+	if o == nil {
+		return false
+	}
+	for _, key := range o.Allow[r.Kind] {
+		if key.Name == r.Key.Name && (len(key.Namespace) == 0 || key.Namespace == r.Key.Namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// References checks that every ServiceAccount, ConfigMap, Secret, PersistentVolumeClaim, Role, ClusterRole, and Service
+// referenced by a rendered object is also rendered by the chart or is allowed to exist before the chart is installed
+func References(opts *ReferenceOptions) ChainedCheckFunc {
+	return OnReferences(func(tc *TestContext, references []Reference) {
+		for _, reference := range references {
+			if reference.Resolved || opts.allows(reference) {
+				continue
+			}
+			tc.T.Errorf("%s, which is not rendered by the chart", reference)
+		}
+	})
+}
+
+// OnReferences runs the check against every reference by name from a rendered workload, RoleBinding,
+// ClusterRoleBinding, Ingress, webhook configuration, or APIService to another object
+func OnReferences(typedCheckFunc func(tc *TestContext, references []Reference)) ChainedCheckFunc {
+	return func(tc *TestContext) CheckFunc {
+		return func(t *testing.T, objs struct {
+			Workloads                       workloadObjects
+			ServiceAccounts                 []*corev1.ServiceAccount
+			ConfigMaps                      []*corev1.ConfigMap
+			Secrets                         []*corev1.Secret
+			PersistentVolumeClaims          []*corev1.PersistentVolumeClaim
+			Services                        []*corev1.Service
+			Roles                           []*rbacv1.Role
+			ClusterRoles                    []*rbacv1.ClusterRole
+			RoleBindings                    []*rbacv1.RoleBinding
+			ClusterRoleBindings             []*rbacv1.ClusterRoleBinding
+			Ingresses                       []*networkingv1.Ingress
+			ValidatingWebhookConfigurations []*admissionregistrationv1.ValidatingWebhookConfiguration
+			MutatingWebhookConfigurations   []*admissionregistrationv1.MutatingWebhookConfiguration
+			APIServices                     []*apiregistrationv1.APIService
+		}) {
+			tc.T = t
+			rendered := map[string]map[relatedresource.Key]bool{}
+			addRendered := func(kind string, obj metav1.Object) {
+				if rendered[kind] == nil {
+					rendered[kind] = map[relatedresource.Key]bool{}
+				}
+				rendered[kind][Key(obj)] = true
+			}
+			for _, obj := range objs.ServiceAccounts {
+				addRendered("ServiceAccount", obj)
+			}
+			for _, obj := range objs.ConfigMaps {
+				addRendered("ConfigMap", obj)
+			}
+			for _, obj := range objs.Secrets {
+				addRendered("Secret", obj)
+			}
+			for _, obj := range objs.PersistentVolumeClaims {
+				addRendered("PersistentVolumeClaim", obj)
+			}
+			for _, obj := range objs.Services {
+				addRendered("Service", obj)
+			}
+			for _, obj := range objs.Roles {
+				addRendered("Role", obj)
+			}
+			for _, obj := range objs.ClusterRoles {
+				addRendered("ClusterRole", obj)
+			}
+
+			var references []Reference
+			add := func(from metav1.Object, field, kind, namespace, name string) {
+				if len(name) == 0 {
+					return
+				}
+				references = append(references, Reference{
+					From:  from,
+					Field: field,
+					Kind:  kind,
+					Key:   relatedresource.NewKey(namespace, name),
+				})
+			}
+			for obj, podTemplateSpec := range objs.Workloads.podTemplateSpecs() {
+				addPodSpecReferences(add, obj, podTemplateSpecField(obj)+".spec", podTemplateSpec.Spec)
+			}
+			for _, obj := range objs.RoleBindings {
+				roleNamespace := obj.Namespace
+				if obj.RoleRef.Kind == "ClusterRole" {
+					roleNamespace = ""
+				}
+				add(obj, "roleRef", obj.RoleRef.Kind, roleNamespace, obj.RoleRef.Name)
+				addSubjectReferences(add, obj, obj.Namespace, obj.Subjects)
+			}
+			for _, obj := range objs.ClusterRoleBindings {
+				add(obj, "roleRef", obj.RoleRef.Kind, "", obj.RoleRef.Name)
+				addSubjectReferences(add, obj, "", obj.Subjects)
+			}
+			for _, obj := range objs.Ingresses {
+				if obj.Spec.DefaultBackend != nil && obj.Spec.DefaultBackend.Service != nil {
+					add(obj, "spec.defaultBackend.service.name", "Service", obj.Namespace, obj.Spec.DefaultBackend.Service.Name)
+				}
+				for i, rule := range obj.Spec.Rules {
+					if rule.HTTP == nil {
+						continue
+					}
+					for j, path := range rule.HTTP.Paths {
+						if path.Backend.Service != nil {
+							add(obj, fmt.Sprintf("spec.rules[%d].http.paths[%d].backend.service.name", i, j), "Service", obj.Namespace, path.Backend.Service.Name)
+						}
+					}
+				}
+				for i, tls := range obj.Spec.TLS {
+					add(obj, fmt.Sprintf("spec.tls[%d].secretName", i), "Secret", obj.Namespace, tls.SecretName)
+				}
+			}
+			for _, obj := range objs.ValidatingWebhookConfigurations {
+				for i, webhook := range obj.Webhooks {
+					if webhook.ClientConfig.Service != nil {
+						add(obj, fmt.Sprintf("webhooks[%d].clientConfig.service", i), "Service", webhook.ClientConfig.Service.Namespace, webhook.ClientConfig.Service.Name)
+					}
+				}
+			}
+			for _, obj := range objs.MutatingWebhookConfigurations {
+				for i, webhook := range obj.Webhooks {
+					if webhook.ClientConfig.Service != nil {
+						add(obj, fmt.Sprintf("webhooks[%d].clientConfig.service", i), "Service", webhook.ClientConfig.Service.Namespace, webhook.ClientConfig.Service.Name)
+					}
+				}
+			}
+			for _, obj := range objs.APIServices {
+				if obj.Spec.Service != nil {
+					add(obj, "spec.service", "Service", obj.Spec.Service.Namespace, obj.Spec.Service.Name)
+				}
+			}
+
+			for i := range references {
+				references[i].Resolved = rendered[references[i].Kind][references[i].Key]
+			}
+			sort.SliceStable(references, func(i, j int) bool {
+				return fmt.Sprintf("%s %T", Key(references[i].From), references[i].From) < fmt.Sprintf("%s %T", Key(references[j].From), references[j].From)
+			})
+			typedCheckFunc(tc, references)
+		}
+	}
+}
+
+func addPodSpecReferences(add func(from metav1.Object, field, kind, namespace, name string), obj metav1.Object, prefix string, podSpec corev1.PodSpec) {
+	namespace := obj.GetNamespace()
+	serviceAccountName := podSpec.ServiceAccountName
+	if len(serviceAccountName) == 0 {
+		serviceAccountName = podSpec.DeprecatedServiceAccount
+	}
+	add(obj, prefix+".serviceAccountName", "ServiceAccount", namespace, serviceAccountName)
+	for i, imagePullSecret := range podSpec.ImagePullSecrets {
+		add(obj, fmt.Sprintf("%s.imagePullSecrets[%d]", prefix, i), "Secret", namespace, imagePullSecret.Name)
+	}
+	for i, volume := range podSpec.Volumes {
+		field := fmt.Sprintf("%s.volumes[%d]", prefix, i)
+		switch {
+		case volume.ConfigMap != nil && !isOptional(volume.ConfigMap.Optional):
+			add(obj, field+".configMap", "ConfigMap", namespace, volume.ConfigMap.Name)
+		case volume.Secret != nil && !isOptional(volume.Secret.Optional):
+			add(obj, field+".secret", "Secret", namespace, volume.Secret.SecretName)
+		case volume.PersistentVolumeClaim != nil:
+			add(obj, field+".persistentVolumeClaim", "PersistentVolumeClaim", namespace, volume.PersistentVolumeClaim.ClaimName)
+		case volume.Projected != nil:
+			for j, source := range volume.Projected.Sources {
+				sourceField := fmt.Sprintf("%s.projected.sources[%d]", field, j)
+				if source.ConfigMap != nil && !isOptional(source.ConfigMap.Optional) {
+					add(obj, sourceField+".configMap", "ConfigMap", namespace, source.ConfigMap.Name)
+				}
+				if source.Secret != nil && !isOptional(source.Secret.Optional) {
+					add(obj, sourceField+".secret", "Secret", namespace, source.Secret.Name)
+				}
+			}
+		}
+	}
+	for _, containers := range []struct {
+		field      string
+		containers []corev1.Container
+	}{
+		{field: "initContainers", containers: podSpec.InitContainers},
+		{field: "containers", containers: podSpec.Containers},
+	} {
+		for i, container := range containers.containers {
+			containerField := fmt.Sprintf("%s.%s[%d]", prefix, containers.field, i)
+			for j, env := range container.Env {
+				if env.ValueFrom == nil {
+					continue
+				}
+				envField := fmt.Sprintf("%s.env[%d].valueFrom", containerField, j)
+				if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil && !isOptional(ref.Optional) {
+					add(obj, envField+".configMapKeyRef", "ConfigMap", namespace, ref.Name)
+				}
+				if ref := env.ValueFrom.SecretKeyRef; ref != nil && !isOptional(ref.Optional) {
+					add(obj, envField+".secretKeyRef", "Secret", namespace, ref.Name)
+				}
+			}
+			for j, envFrom := range container.EnvFrom {
+				envFromField := fmt.Sprintf("%s.envFrom[%d]", containerField, j)
+				if ref := envFrom.ConfigMapRef; ref != nil && !isOptional(ref.Optional) {
+					add(obj, envFromField+".configMapRef", "ConfigMap", namespace, ref.Name)
+				}
+				if ref := envFrom.SecretRef; ref != nil && !isOptional(ref.Optional) {
+					add(obj, envFromField+".secretRef", "Secret", namespace, ref.Name)
+				}
+			}
+		}
+	}
+}
+
+func addSubjectReferences(add func(from metav1.Object, field, kind, namespace, name string), obj metav1.Object, namespace string, subjects []rbacv1.Subject) {
+	for i, subject := range subjects {
+		if subject.Kind != rbacv1.ServiceAccountKind {
+			continue
+		}
+		subjectNamespace := subject.Namespace
+		if len(subjectNamespace) == 0 {
+			subjectNamespace = namespace
+		}
+		add(obj, fmt.Sprintf("subjects[%d]", i), "ServiceAccount", subjectNamespace, subject.Name)
+	}
+}
+
+func isOptional(optional *bool) bool {
+	return optional != nil && *optional
+}
@@ -0,0 +1,182 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/stretchr/testify/assert"
+)
+
+const referencesManifest = `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: hull
+  namespace: hull
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: hull
+  namespace: hull
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hull
+  namespace: hull
+spec:
+  selector:
+    matchLabels:
+      app: hull
+  template:
+    metadata:
+      labels:
+        app: hull
+    spec:
+      serviceAccountName: hull
+      containers:
+      - name: hull
+        image: rancher/hull:v0.1.0
+        envFrom:
+        - secretRef:
+            name: credentials
+      volumes:
+      - name: config
+        configMap:
+          name: missing-config
+      - name: optional
+        secret:
+          secretName: optional-secret
+          optional: true
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: hull
+  namespace: hull
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: view
+subjects:
+- kind: ServiceAccount
+  name: hull
+- kind: User
+  name: admin
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: hull
+  namespace: hull
+spec:
+  rules:
+  - http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: hull
+            port:
+              number: 80
+---
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: hull
+webhooks:
+- name: hull.cattle.io
+  admissionReviewVersions: ["v1"]
+  sideEffects: None
+  clientConfig:
+    service:
+      name: hull-webhook
+      namespace: hull
+`
+
+func TestOnReferences(t *testing.T) {
+	c, err := NewCheckerFromString(referencesManifest, "manifest.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var references []string
+	var resolved []bool
+	c.Check(t, NewCheckFunc(OnReferences(func(tc *TestContext, refs []Reference) {
+		for _, ref := range refs {
+			references = append(references, ref.String())
+			resolved = append(resolved, ref.Resolved)
+		}
+	})))
+	assert.Equal(t, []string{
+		"*v1.ValidatingWebhookConfiguration { hull} references Service {hull hull-webhook} at webhooks[0].clientConfig.service",
+		"*v1.Deployment {hull hull} references ServiceAccount {hull hull} at spec.template.spec.serviceAccountName",
+		"*v1.Deployment {hull hull} references ConfigMap {hull missing-config} at spec.template.spec.volumes[0].configMap",
+		"*v1.Deployment {hull hull} references Secret {hull credentials} at spec.template.spec.containers[0].envFrom[0].secretRef",
+		"*v1.Ingress {hull hull} references Service {hull hull} at spec.rules[0].http.paths[0].backend.service.name",
+		"*v1.RoleBinding {hull hull} references ClusterRole { view} at roleRef",
+		"*v1.RoleBinding {hull hull} references ServiceAccount {hull hull} at subjects[0]",
+	}, references)
+	assert.Equal(t, []bool{false, true, false, false, true, false, true}, resolved)
+}
+
+func TestReferences(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Options       *ReferenceOptions
+		ExpectFailure bool
+	}{
+		{
+			Name:          "Dangling References",
+			Options:       nil,
+			ExpectFailure: true,
+		},
+		{
+			Name: "Some Dangling References Allowed",
+			Options: &ReferenceOptions{
+				Allow: map[string][]relatedresource.Key{
+					"Secret":    {relatedresource.NewKey("", "credentials")},
+					"ConfigMap": {relatedresource.NewKey("hull", "missing-config")},
+				},
+			},
+			ExpectFailure: true,
+		},
+		{
+			Name: "Allowed In Other Namespace",
+			Options: &ReferenceOptions{
+				Allow: map[string][]relatedresource.Key{
+					"Secret":    {relatedresource.NewKey("", "credentials")},
+					"ConfigMap": {relatedresource.NewKey("default", "missing-config")},
+					"Service":   {relatedresource.NewKey("hull", "hull-webhook")},
+				},
+			},
+			ExpectFailure: true,
+		},
+		{
+			Name: "All Dangling References Allowed",
+			Options: &ReferenceOptions{
+				Allow: map[string][]relatedresource.Key{
+					"Secret":    {relatedresource.NewKey("", "credentials")},
+					"ConfigMap": {relatedresource.NewKey("hull", "missing-config")},
+					"Service":   {relatedresource.NewKey("hull", "hull-webhook")},
+				},
+			},
+		},
+	}
+
+	c, err := NewCheckerFromString(referencesManifest, "manifest.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			fakeT := &testing.T{}
+			c.Check(fakeT, NewCheckFunc(References(tc.Options)))
+			assert.Equal(t, tc.ExpectFailure, fakeT.Failed())
+		})
+	}
+}
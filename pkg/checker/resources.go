@@ -12,6 +12,16 @@ func Key[O metav1.Object](obj O) relatedresource.Key {
 	return relatedresource.NewKey(obj.GetNamespace(), obj.GetName())
 }
 
+// IsExempt returns whether the key (i.e. the checker.Key of an object) is one of the exempt keys
+func IsExempt(exempt []relatedresource.Key, key relatedresource.Key) bool {
+	for _, exemptKey := range exempt {
+		if exemptKey == key {
+			return true
+		}
+	}
+	return false
+}
+
 func Select[O metav1.Object](name string, namespace string, obj O) bool {
 	return obj.GetName() == name && obj.GetNamespace() == namespace
 }
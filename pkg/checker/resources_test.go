@@ -45,6 +45,43 @@ func TestKey(t *testing.T) {
 	}
 }
 
+func TestIsExempt(t *testing.T) {
+	exempt := []relatedresource.Key{
+		relatedresource.NewKey("", "global-resource"),
+		relatedresource.NewKey("my-namespace", "namespaced-resource"),
+	}
+	testCases := []struct {
+		Name   string
+		Key    relatedresource.Key
+		Expect bool
+	}{
+		{
+			Name:   "Exempt Global Resource",
+			Key:    relatedresource.NewKey("", "global-resource"),
+			Expect: true,
+		},
+		{
+			Name:   "Exempt Namespaced Resource",
+			Key:    relatedresource.NewKey("my-namespace", "namespaced-resource"),
+			Expect: true,
+		},
+		{
+			Name: "Same Name In Different Namespace",
+			Key:  relatedresource.NewKey("other-namespace", "namespaced-resource"),
+		},
+		{
+			Name: "Not Exempt",
+			Key:  relatedresource.NewKey("my-namespace", "other-resource"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expect, IsExempt(exempt, tc.Key))
+		})
+	}
+}
+
 func TestSelect(t *testing.T) {
 	testCases := []struct {
 		Name         string
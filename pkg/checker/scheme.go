@@ -2,68 +2,41 @@ package checker
 
 import (
 	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 
-	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
-	appsv1 "k8s.io/api/apps/v1"
-	autoscalingv1 "k8s.io/api/autoscaling/v1"
-	batchv1 "k8s.io/api/batch/v1"
-	corev1 "k8s.io/api/core/v1"
-	networkingv1 "k8s.io/api/networking/v1"
-	policyv1beta1 "k8s.io/api/policy/v1beta1"
-	rbacv1 "k8s.io/api/rbac/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 )
 
 var (
+	// Scheme contains the types that rendered objects are converted into before they are passed to a CheckFunc
+	//
+	// Objects whose types are not registered can only be received as *unstructured.Unstructured.
 	Scheme = runtime.NewScheme()
 )
 
 func init() {
-	// workloads
-	if err := appsv1.AddToScheme(Scheme); err != nil {
-		panic(err)
-	}
-	if err := autoscalingv1.AddToScheme(Scheme); err != nil {
-		panic(err)
-	}
-	if err := batchv1.AddToScheme(Scheme); err != nil {
-		panic(err)
-	}
-	if err := corev1.AddToScheme(Scheme); err != nil {
-		panic(err)
-	}
-	if err := policyv1beta1.AddToScheme(Scheme); err != nil {
-		panic(err)
-	}
-
-	// rbac
-	if err := corev1.AddToScheme(Scheme); err != nil {
-		panic(err)
-	}
-	if err := rbacv1.AddToScheme(Scheme); err != nil {
-		panic(err)
-	}
-
-	// networking
-	if err := corev1.AddToScheme(Scheme); err != nil {
-		panic(err)
-	}
-	if err := networkingv1.AddToScheme(Scheme); err != nil {
-		panic(err)
-	}
-	if err := apiregistrationv1.AddToScheme(Scheme); err != nil {
+	if err := RegisterScheme(
+		// every group in k8s.io/api
+		clientgoscheme.AddToScheme,
+		// globals
+		apiextensionsv1.AddToScheme,
+		// networking
+		apiregistrationv1.AddToScheme,
+	); err != nil {
 		panic(err)
 	}
+}
 
-	// globals
-	if err := admissionregistrationv1.AddToScheme(Scheme); err != nil {
-		panic(err)
-	}
-	if err := apiextensionsv1.AddToScheme(Scheme); err != nil {
-		panic(err)
-	}
-	if err := corev1.AddToScheme(Scheme); err != nil {
-		panic(err)
-	}
+// RegisterScheme adds types to the Scheme so that typed checks can receive them, i.e. the Go types of CRDs like
+// monitoringv1.AddToScheme for Prometheus ServiceMonitors
+//
+// Registered types must implement DeepCopyInto (as generated by deepcopy-gen) to be converted from rendered objects;
+// otherwise, they are received as *unstructured.Unstructured.
+//
+// Since the Scheme is shared by all checks, types should be registered before any checks run, i.e. in an init function
+// or TestMain.
+func RegisterScheme(addToSchemes ...func(*runtime.Scheme) error) error {
+	schemeBuilder := runtime.NewSchemeBuilder(addToSchemes...)
+	return schemeBuilder.AddToScheme(Scheme)
 }
@@ -0,0 +1,136 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const schemeManifest = `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: hull
+  namespace: hull
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: hull
+  maxReplicas: 3
+---
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: hull
+  namespace: hull
+spec:
+  maxUnavailable: 1
+---
+apiVersion: storage.k8s.io/v1
+kind: StorageClass
+metadata:
+  name: hull
+provisioner: rancher.io/local-path
+---
+apiVersion: scheduling.k8s.io/v1
+kind: PriorityClass
+metadata:
+  name: hull
+value: 1000
+---
+apiVersion: coordination.k8s.io/v1
+kind: Lease
+metadata:
+  name: hull
+  namespace: hull
+---
+apiVersion: hull.cattle.io/v1
+kind: Widget
+metadata:
+  name: hull
+  namespace: hull
+spec:
+  size: 3
+`
+
+var widgetGroupVersion = schema.GroupVersion{Group: "hull.cattle.io", Version: "v1"}
+
+type widget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Size int `json:"size"`
+	} `json:"spec"`
+}
+
+func (w *widget) DeepCopyInto(out *widget) {
+	*out = *w
+	w.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+}
+
+func (w *widget) DeepCopyObject() runtime.Object {
+	out := &widget{}
+	w.DeepCopyInto(out)
+	return out
+}
+
+func TestScheme(t *testing.T) {
+	c, err := NewCheckerFromString(schemeManifest, "manifest.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Check(t, func(t *testing.T, objs struct {
+		HorizontalPodAutoscalers []*autoscalingv2.HorizontalPodAutoscaler
+		PodDisruptionBudgets     []*policyv1.PodDisruptionBudget
+		StorageClasses           []*storagev1.StorageClass
+		PriorityClasses          []*schedulingv1.PriorityClass
+		Leases                   []*coordinationv1.Lease
+	}) {
+		if assert.Len(t, objs.HorizontalPodAutoscalers, 1) {
+			assert.Equal(t, int32(3), objs.HorizontalPodAutoscalers[0].Spec.MaxReplicas)
+		}
+		assert.Len(t, objs.PodDisruptionBudgets, 1)
+		if assert.Len(t, objs.StorageClasses, 1) {
+			assert.Equal(t, "rancher.io/local-path", objs.StorageClasses[0].Provisioner)
+		}
+		if assert.Len(t, objs.PriorityClasses, 1) {
+			assert.Equal(t, int32(1000), objs.PriorityClasses[0].Value)
+		}
+		assert.Len(t, objs.Leases, 1)
+	})
+}
+
+func TestRegisterScheme(t *testing.T) {
+	err := RegisterScheme(func(s *runtime.Scheme) error {
+		s.AddKnownTypeWithName(widgetGroupVersion.WithKind("Widget"), &widget{})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewCheckerFromString(schemeManifest, "manifest.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Check(t, func(t *testing.T, objs struct {
+		Widgets      []*widget
+		Unstructured []*unstructured.Unstructured
+	}) {
+		if assert.Len(t, objs.Widgets, 1) {
+			assert.Equal(t, 3, objs.Widgets[0].Spec.Size)
+		}
+		for _, obj := range objs.Unstructured {
+			assert.NotEqual(t, "Widget", obj.GetKind())
+		}
+	})
+}
@@ -0,0 +1,219 @@
+package checker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// SelectorOptions configures Selectors
+type SelectorOptions struct {
+	// Exempt are the objects whose selectors are expected to select pods or Services that are not rendered by the
+	// chart, keyed by checker.Key
+	Exempt []relatedresource.Key
+}
+
+func (o *SelectorOptions) exempts(obj metav1.Object) bool {
+	if o == nil {
+		return false
+	}
+	return IsExempt(o.Exempt, Key(obj))
+}
+
+// serviceMonitor contains the fields of a monitoring.coreos.com/v1 ServiceMonitor that select Services
+type serviceMonitor struct {
+	Spec struct {
+		Selector          metav1.LabelSelector `json:"selector"`
+		NamespaceSelector struct {
+			Any        bool     `json:"any"`
+			MatchNames []string `json:"matchNames"`
+		} `json:"namespaceSelector"`
+		Endpoints []struct {
+			Port string `json:"port"`
+		} `json:"endpoints"`
+	} `json:"spec"`
+}
+
+// Selectors checks that the selector of every Service, NetworkPolicy, and PodDisruptionBudget matches the pods of at
+// least one workload in its namespace and that the ports of Services and NetworkPolicies are exposed by those pods,
+// along with checking that the selector of every ServiceMonitor matches a Service with the ports of its endpoints
+func Selectors(opts *SelectorOptions) ChainedCheckFunc {
+	return func(tc *TestContext) CheckFunc {
+		return func(t *testing.T, objs struct {
+			Workloads            workloadObjects
+			Services             []*corev1.Service
+			NetworkPolicies      []*networkingv1.NetworkPolicy
+			PodDisruptionBudgets []*policyv1.PodDisruptionBudget
+			// policy/v1beta1 PodDisruptionBudgets are rendered for Kubernetes versions older than 1.21
+			PodDisruptionBudgetsV1beta1 []*policyv1beta1.PodDisruptionBudget
+			Unstructured                []*unstructured.Unstructured
+		}) {
+			tc.T = t
+			podTemplateSpecs := objs.Workloads.podTemplateSpecs()
+			selectPods := func(namespace string, selector labels.Selector) []corev1.PodTemplateSpec {
+				var selected []corev1.PodTemplateSpec
+				for obj, podTemplateSpec := range podTemplateSpecs {
+					if obj.GetNamespace() == namespace && selector.Matches(labels.Set(podTemplateSpec.Labels)) {
+						selected = append(selected, podTemplateSpec)
+					}
+				}
+				return selected
+			}
+
+			for _, service := range objs.Services {
+				if opts.exempts(service) || service.Spec.Type == corev1.ServiceTypeExternalName || len(service.Spec.Selector) == 0 {
+					continue
+				}
+				selector := labels.SelectorFromSet(service.Spec.Selector)
+				selected := selectPods(service.Namespace, selector)
+				if len(selected) == 0 {
+					t.Errorf("%T %s has selector %s that does not match the pods of any workload", service, Key(service), selector)
+					continue
+				}
+				for i, port := range service.Spec.Ports {
+					targetPort := port.TargetPort
+					if targetPort.Type == intstr.Int && targetPort.IntVal == 0 {
+						targetPort = intstr.FromInt32(port.Port)
+					}
+					if err := checkTargetPort(selected, targetPort); err != nil {
+						t.Errorf("%T %s has port spec.ports[%d] that targets %s", service, Key(service), i, err)
+					}
+				}
+			}
+
+			for _, networkPolicy := range objs.NetworkPolicies {
+				if opts.exempts(networkPolicy) {
+					continue
+				}
+				selector, err := metav1.LabelSelectorAsSelector(&networkPolicy.Spec.PodSelector)
+				if err != nil {
+					t.Errorf("%T %s has invalid podSelector: %s", networkPolicy, Key(networkPolicy), err)
+					continue
+				}
+				selected := selectPods(networkPolicy.Namespace, selector)
+				if !selector.Empty() && len(selected) == 0 {
+					t.Errorf("%T %s has podSelector %s that does not match the pods of any workload", networkPolicy, Key(networkPolicy), selector)
+					continue
+				}
+				for i, ingress := range networkPolicy.Spec.Ingress {
+					for j, port := range ingress.Ports {
+						if port.Port == nil || port.Port.Type != intstr.String || len(selected) == 0 {
+							continue
+						}
+						if err := checkTargetPort(selected, *port.Port); err != nil {
+							t.Errorf("%T %s has port spec.ingress[%d].ports[%d] that allows %s", networkPolicy, Key(networkPolicy), i, j, err)
+						}
+					}
+				}
+			}
+
+			checkPodDisruptionBudget := func(podDisruptionBudget metav1.Object, labelSelector *metav1.LabelSelector) {
+				if opts.exempts(podDisruptionBudget) || labelSelector == nil {
+					return
+				}
+				selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+				if err != nil {
+					t.Errorf("%T %s has invalid selector: %s", podDisruptionBudget, Key(podDisruptionBudget), err)
+					return
+				}
+				if len(selectPods(podDisruptionBudget.GetNamespace(), selector)) == 0 {
+					t.Errorf("%T %s has selector %s that does not match the pods of any workload", podDisruptionBudget, Key(podDisruptionBudget), selector)
+				}
+			}
+			for _, podDisruptionBudget := range objs.PodDisruptionBudgets {
+				checkPodDisruptionBudget(podDisruptionBudget, podDisruptionBudget.Spec.Selector)
+			}
+			for _, podDisruptionBudget := range objs.PodDisruptionBudgetsV1beta1 {
+				checkPodDisruptionBudget(podDisruptionBudget, podDisruptionBudget.Spec.Selector)
+			}
+
+			for _, obj := range objs.Unstructured {
+				gvk := obj.GroupVersionKind()
+				if gvk.Group != "monitoring.coreos.com" || gvk.Kind != "ServiceMonitor" || opts.exempts(obj) {
+					continue
+				}
+				checkServiceMonitor(t, obj, objs.Services)
+			}
+		}
+	}
+}
+
+// checkTargetPort returns an error if no container of the pods exposes the port; numeric ports are only checked if
+// the containers declare ports
+func checkTargetPort(podTemplateSpecs []corev1.PodTemplateSpec, targetPort intstr.IntOrString) error {
+	var declaresPorts bool
+	for _, podTemplateSpec := range podTemplateSpecs {
+		for _, container := range podTemplateSpec.Spec.Containers {
+			for _, port := range container.Ports {
+				declaresPorts = true
+				if targetPort.Type == intstr.String && port.Name == targetPort.StrVal {
+					return nil
+				}
+				if targetPort.Type == intstr.Int && port.ContainerPort == targetPort.IntVal {
+					return nil
+				}
+			}
+		}
+	}
+	if targetPort.Type == intstr.String {
+		return fmt.Errorf("port %q, which is not the name of a port of any selected container", targetPort.StrVal)
+	}
+	if !declaresPorts {
+		return nil
+	}
+	return fmt.Errorf("port %d, which is not a port of any selected container", targetPort.IntVal)
+}
+
+func checkServiceMonitor(t *testing.T, obj *unstructured.Unstructured, services []*corev1.Service) {
+	var monitor serviceMonitor
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &monitor); err != nil {
+		t.Errorf("unable to parse ServiceMonitor %s: %s", Key(obj), err)
+		return
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&monitor.Spec.Selector)
+	if err != nil {
+		t.Errorf("ServiceMonitor %s has invalid selector: %s", Key(obj), err)
+		return
+	}
+	namespaces := map[string]bool{obj.GetNamespace(): true}
+	if len(monitor.Spec.NamespaceSelector.MatchNames) > 0 {
+		namespaces = map[string]bool{}
+		for _, namespace := range monitor.Spec.NamespaceSelector.MatchNames {
+			namespaces[namespace] = true
+		}
+	}
+	portNames := map[string]bool{}
+	var selected bool
+	for _, service := range services {
+		if !monitor.Spec.NamespaceSelector.Any && !namespaces[service.Namespace] {
+			continue
+		}
+		if !selector.Matches(labels.Set(service.Labels)) {
+			continue
+		}
+		selected = true
+		for _, port := range service.Spec.Ports {
+			portNames[port.Name] = true
+		}
+	}
+	if !selected {
+		t.Errorf("ServiceMonitor %s has selector %s that does not match any Service", Key(obj), selector)
+		return
+	}
+	for i, endpoint := range monitor.Spec.Endpoints {
+		if len(endpoint.Port) > 0 && !portNames[endpoint.Port] {
+			t.Errorf("ServiceMonitor %s has endpoint spec.endpoints[%d] that targets port %q, which is not the name of a port of any selected Service", Key(obj), i, endpoint.Port)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/stretchr/testify/assert"
+)
+
+const selectorsWorkloadManifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hull
+  namespace: hull
+spec:
+  selector:
+    matchLabels:
+      app: hull
+  template:
+    metadata:
+      labels:
+        app: hull
+    spec:
+      containers:
+      - name: hull
+        image: rancher/hull:v0.1.0
+        ports:
+        - name: http
+          containerPort: 8080
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: hull
+  namespace: hull
+  labels:
+    app: hull
+spec:
+  selector:
+    app: hull
+  ports:
+  - name: http
+    port: 80
+    targetPort: http
+`
+
+func TestSelectors(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Manifest      string
+		Options       *SelectorOptions
+		ExpectFailure bool
+	}{
+		{
+			Name: "Matching Service",
+		},
+		{
+			Name: "Service With Numeric Target Port",
+			Manifest: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: numeric
+  namespace: hull
+spec:
+  selector:
+    app: hull
+  ports:
+  - port: 8080
+`,
+		},
+		{
+			Name: "Service Without Selector",
+			Manifest: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: external
+  namespace: hull
+spec:
+  ports:
+  - port: 80
+`,
+		},
+		{
+			Name: "Service Selects No Pods",
+			Manifest: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: mismatched
+  namespace: hull
+spec:
+  selector:
+    app: hull-typo
+  ports:
+  - port: 80
+`,
+			ExpectFailure: true,
+		},
+		{
+			Name: "Exempt Service Selects No Pods",
+			Manifest: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: mismatched
+  namespace: hull
+spec:
+  selector:
+    app: hull-typo
+  ports:
+  - port: 80
+`,
+			Options: &SelectorOptions{Exempt: []relatedresource.Key{relatedresource.NewKey("hull", "mismatched")}},
+		},
+		{
+			Name: "Service Selects Pods In Other Namespace",
+			Manifest: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: hull
+  namespace: default
+spec:
+  selector:
+    app: hull
+  ports:
+  - port: 80
+    targetPort: http
+`,
+			ExpectFailure: true,
+		},
+		{
+			Name: "Service Targets Missing Named Port",
+			Manifest: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: metrics
+  namespace: hull
+spec:
+  selector:
+    app: hull
+  ports:
+  - port: 80
+    targetPort: metrics
+`,
+			ExpectFailure: true,
+		},
+		{
+			Name: "Service Targets Missing Numeric Port",
+			Manifest: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: numeric
+  namespace: hull
+spec:
+  selector:
+    app: hull
+  ports:
+  - port: 80
+`,
+			ExpectFailure: true,
+		},
+		{
+			Name: "NetworkPolicy Selecting All Pods",
+			Manifest: `
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: default-deny
+  namespace: other
+spec:
+  podSelector: {}
+`,
+		},
+		{
+			Name: "NetworkPolicy Selects No Pods",
+			Manifest: `
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: hull
+  namespace: hull
+spec:
+  podSelector:
+    matchLabels:
+      app: hull-typo
+`,
+			ExpectFailure: true,
+		},
+		{
+			Name: "NetworkPolicy Allows Missing Named Port",
+			Manifest: `
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: hull
+  namespace: hull
+spec:
+  podSelector:
+    matchLabels:
+      app: hull
+  ingress:
+  - ports:
+    - port: http
+    - port: metrics
+`,
+			ExpectFailure: true,
+		},
+		{
+			Name: "Matching PodDisruptionBudget",
+			Manifest: `
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: hull
+  namespace: hull
+spec:
+  maxUnavailable: 1
+  selector:
+    matchLabels:
+      app: hull
+`,
+		},
+		{
+			Name: "PodDisruptionBudget Selects No Pods",
+			Manifest: `
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: hull
+  namespace: hull
+spec:
+  maxUnavailable: 1
+  selector:
+    matchExpressions:
+    - key: app
+      operator: In
+      values: [hull-typo]
+`,
+			ExpectFailure: true,
+		},
+		{
+			Name: "Matching v1beta1 PodDisruptionBudget",
+			Manifest: `
+apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+metadata:
+  name: hull
+  namespace: hull
+spec:
+  maxUnavailable: 1
+  selector:
+    matchLabels:
+      app: hull
+`,
+		},
+		{
+			Name: "v1beta1 PodDisruptionBudget Selects No Pods",
+			Manifest: `
+apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+metadata:
+  name: hull
+  namespace: hull
+spec:
+  maxUnavailable: 1
+  selector:
+    matchLabels:
+      app: hull-typo
+`,
+			ExpectFailure: true,
+		},
+		{
+			Name: "Matching ServiceMonitor",
+			Manifest: `
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: hull
+  namespace: monitoring
+spec:
+  namespaceSelector:
+    matchNames: [hull]
+  selector:
+    matchLabels:
+      app: hull
+  endpoints:
+  - port: http
+`,
+		},
+		{
+			Name: "ServiceMonitor Selects No Services",
+			Manifest: `
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: hull
+  namespace: monitoring
+spec:
+  selector:
+    matchLabels:
+      app: hull
+  endpoints:
+  - port: http
+`,
+			ExpectFailure: true,
+		},
+		{
+			Name: "ServiceMonitor Targets Missing Port",
+			Manifest: `
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: hull
+  namespace: hull
+spec:
+  selector:
+    matchLabels:
+      app: hull
+  endpoints:
+  - port: metrics
+`,
+			ExpectFailure: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			manifest := selectorsWorkloadManifest
+			if len(tc.Manifest) > 0 {
+				manifest += "---" + tc.Manifest
+			}
+			c, err := NewCheckerFromString(manifest, "manifest.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			fakeT := &testing.T{}
+			c.Check(fakeT, NewCheckFunc(Selectors(tc.Options)))
+			assert.Equal(t, tc.ExpectFailure, fakeT.Failed())
+		})
+	}
+}
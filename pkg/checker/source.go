@@ -0,0 +1,108 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rancher/hull/pkg/checker/internal"
+	"github.com/rancher/wrangler/v3/pkg/objectset"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Source is the location in the templates of a chart that a rendered object was rendered from
+type Source struct {
+	// Template is the path of the template within the chart, i.e. templates/deployment.yaml
+	Template string
+
+	// Lines are the lines of the template that rendered the object, if known
+	Lines LineRange
+
+	// Fields are the lines of the template that rendered each top-level field of the object (i.e. spec), if known
+	Fields map[string]LineRange
+}
+
+// LineRange is an inclusive range of lines, starting from 1; the zero value is an unknown range
+type LineRange struct {
+	Start int
+	End   int
+}
+
+func (r LineRange) String() string {
+	if r.Start == 0 {
+		return ""
+	}
+	if r.Start == r.End {
+		return fmt.Sprint(r.Start)
+	}
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
+}
+
+// String returns the location of the object, i.e. templates/deployment.yaml:12-40
+func (s Source) String() string {
+	if s.Lines.Start == 0 {
+		return s.Template
+	}
+	return fmt.Sprintf("%s:%s", s.Template, s.Lines)
+}
+
+// Field returns the location of the top-level field of the path (i.e. spec for .spec.replicas or
+// spec.template.spec.containers[0].image), falling back to the location of the object if it is unknown
+func (s Source) Field(path string) string {
+	field := strings.TrimPrefix(path, ".")
+	if i := strings.IndexAny(field, ".["); i >= 0 {
+		field = field[:i]
+	}
+	lines, ok := s.Fields[field]
+	if !ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%s:%s", s.Template, lines)
+}
+
+// SourceOf returns the Source of a rendered object that the current ChainedCheckFunc is run against, or of an object
+// that it received that was decoded from such an object, or the zero Source if it is unknown
+func (tc *TestContext) SourceOf(obj runtime.Object) Source {
+	if source, ok := tc.sources[obj]; ok {
+		return source
+	}
+	return tc.decodedSources[obj]
+}
+
+// sourcesOf returns the Source of every object in the osMap, which is the template whose path it is keyed by unless
+// sources provides a more precise Source
+func sourcesOf(osMap map[string]*objectset.ObjectSet, sources map[runtime.Object]Source) map[runtime.Object]Source {
+	objSources := make(map[runtime.Object]Source)
+	for osPath, os := range osMap {
+		if len(osPath) == 0 {
+			continue
+		}
+		for _, obj := range os.All() {
+			source, ok := sources[obj]
+			if !ok {
+				source = Source{Template: osPath}
+			}
+			objSources[obj] = source
+		}
+	}
+	return objSources
+}
+
+// sourcedCheckFunc is the CheckFunc returned by NewCheckFunc, which is provided with the Sources of the objects it is
+// run against by the Checker
+type sourcedCheckFunc func(t *testing.T, u struct{ Unstructured []*unstructured.Unstructured }, sources ...map[runtime.Object]Source)
+
+// wrapFunc wraps the CheckFunc so that the objects it receives are keyed by the template they were rendered from
+func wrapFunc(checkFunc CheckFunc, opts *internal.ParseOptions, sources map[runtime.Object]Source) internal.DoFunc {
+	if f, ok := checkFunc.(sourcedCheckFunc); ok {
+		checkFunc = func(t *testing.T, u struct{ Unstructured []*unstructured.Unstructured }) {
+			f(t, u, sources)
+		}
+	}
+	wrapOpts := *opts
+	wrapOpts.SourceOf = func(obj runtime.Object) string {
+		return sources[obj].Template
+	}
+	return internal.WrapFunc(checkFunc, &wrapOpts)
+}
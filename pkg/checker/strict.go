@@ -0,0 +1,93 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rancher/hull/pkg/checker/internal"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// StrictDecoding catches rendered objects that checks silently ignore
+//
+// Every CheckFunc created by its NewCheckFunc records the rendered objects that are accepted by the structs of its
+// ChainedCheckFuncs. Once they have run, the CheckFunc returned by Check fails on every rendered object that none of
+// them accepted and on every non-empty field of a rendered object that is dropped when it is decoded into the Go type
+// registered for it in the Scheme (i.e. imagePullPolicy set on a pod spec instead of a container).
+type StrictDecoding struct {
+	// AllowUnchecked skips reporting rendered objects that were not accepted by any CheckFunc
+	AllowUnchecked bool
+
+	accepted   map[strictDecodingKey]bool
+	incomplete bool
+}
+
+type strictDecodingKey struct {
+	GVK schema.GroupVersionKind
+	Key relatedresource.Key
+}
+
+func NewStrictDecoding() *StrictDecoding {
+	return &StrictDecoding{
+		accepted: make(map[strictDecodingKey]bool),
+	}
+}
+
+// NewCheckFunc is like checker.NewCheckFunc, but records the rendered objects accepted by the ChainedCheckFuncs
+func (d *StrictDecoding) NewCheckFunc(funcs ...ChainedCheckFunc) CheckFunc {
+	return newCheckFunc(d, funcs)
+}
+
+// Check returns a CheckFunc that fails on every rendered object that was dropped or lost fields while running the
+// CheckFuncs created by NewCheckFunc
+//
+// Objects that were not accepted are not reported if any of those CheckFuncs stopped early due to a failure.
+func (d *StrictDecoding) Check() CheckFunc {
+	return func(t *testing.T, objs struct{ Unstructured []*unstructured.Unstructured }) {
+		for _, obj := range objs.Unstructured {
+			lostFields, err := internal.LostFields(Scheme, obj)
+			if err != nil {
+				t.Errorf("unable to decode %s %s: %s", obj.GetKind(), Key(obj), err)
+				continue
+			}
+			if len(lostFields) > 0 {
+				t.Errorf("%s %s has fields that are dropped when it is decoded: %s", obj.GetKind(), Key(obj), strings.Join(lostFields, ", "))
+			}
+			if d.AllowUnchecked || d.incomplete || d.accepted[newStrictDecodingKey(obj)] {
+				continue
+			}
+			t.Errorf("%s %s was not accepted by any check", obj.GetKind(), Key(obj))
+		}
+	}
+}
+
+func (d *StrictDecoding) record(obj runtime.Object) {
+	metaObj, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+	d.accepted[newStrictDecodingKey(metaObj)] = true
+}
+
+func (d *StrictDecoding) stopped() {
+	if d == nil {
+		return
+	}
+	d.incomplete = true
+}
+
+func newStrictDecodingKey(obj metav1.Object) strictDecodingKey {
+	var gvk schema.GroupVersionKind
+	if runtimeObj, ok := obj.(runtime.Object); ok {
+		gvk = runtimeObj.GetObjectKind().GroupVersionKind()
+	}
+	return strictDecodingKey{
+		GVK: gvk,
+		Key: Key(obj),
+	}
+}
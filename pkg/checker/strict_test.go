@@ -0,0 +1,142 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const strictDecodingManifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hull
+  namespace: hull
+spec:
+  selector:
+    matchLabels:
+      app: hull
+  template:
+    metadata:
+      labels:
+        app: hull
+    spec:
+      containers:
+      - name: hull
+        image: rancher/hull:v0.1.0
+        resources: {}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: hull
+  namespace: hull
+data:
+  config: ""
+`
+
+func TestStrictDecoding(t *testing.T) {
+	onDeployments := NewChainedCheckFunc(func(tc *TestContext, objs struct{ Deployments []*appsv1.Deployment }) {})
+	onConfigMaps := NewChainedCheckFunc(func(tc *TestContext, objs struct{ ConfigMaps []*corev1.ConfigMap }) {})
+	failing := Once(func(tc *TestContext) {
+		tc.T.Error("failed")
+	})
+
+	testCases := []struct {
+		Name           string
+		Manifest       string
+		Checks         [][]ChainedCheckFunc
+		AllowUnchecked bool
+		ExpectFailure  bool
+	}{
+		{
+			Name:   "All Objects Accepted",
+			Checks: [][]ChainedCheckFunc{{onDeployments}, {onConfigMaps}},
+		},
+		{
+			Name:          "Object Not Accepted",
+			Checks:        [][]ChainedCheckFunc{{onDeployments}},
+			ExpectFailure: true,
+		},
+		{
+			Name:           "Object Not Accepted Is Allowed",
+			Checks:         [][]ChainedCheckFunc{{onDeployments}},
+			AllowUnchecked: true,
+		},
+		{
+			Name:   "Object Not Accepted After Failure",
+			Checks: [][]ChainedCheckFunc{{onDeployments}, {failing, onConfigMaps}},
+		},
+		{
+			Name: "Misplaced Field",
+			Manifest: `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: hull
+  namespace: hull
+spec:
+  imagePullPolicy: Always
+  containers:
+  - name: hull
+    image: rancher/hull:v0.1.0
+`,
+			Checks:         [][]ChainedCheckFunc{{onDeployments}},
+			AllowUnchecked: true,
+			ExpectFailure:  true,
+		},
+		{
+			Name: "Misspelled Field",
+			Manifest: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: hull
+  namespace: hull
+spec:
+  selectors:
+    app: hull
+`,
+			Checks:         [][]ChainedCheckFunc{{onDeployments}},
+			AllowUnchecked: true,
+			ExpectFailure:  true,
+		},
+		{
+			Name: "Fields Of Unknown Types Are Not Checked",
+			Manifest: `
+apiVersion: hull.cattle.io/v1
+kind: Gizmo
+metadata:
+  name: hull
+  namespace: hull
+spec:
+  anything: goes
+`,
+			Checks:         [][]ChainedCheckFunc{{onDeployments}},
+			AllowUnchecked: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			manifest := strictDecodingManifest
+			if len(tc.Manifest) > 0 {
+				manifest += "---" + tc.Manifest
+			}
+			c, err := NewCheckerFromString(manifest, "manifest.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			strictDecoding := NewStrictDecoding()
+			strictDecoding.AllowUnchecked = tc.AllowUnchecked
+			for _, checks := range tc.Checks {
+				c.Check(&testing.T{}, strictDecoding.NewCheckFunc(checks...))
+			}
+			fakeT := &testing.T{}
+			c.Check(fakeT, strictDecoding.Check())
+			assert.Equal(t, tc.ExpectFailure, fakeT.Failed())
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ImmutableFields tracks the fields of each GroupKind that cannot be modified on an existing object,
+// which would cause a `helm upgrade` that modifies them to fail
+//
+// Additional entries can be added to this map to check immutable fields on other resources (i.e. CRDs).
+var ImmutableFields = map[schema.GroupKind][]string{
+	{Group: "apps", Kind: "Deployment"}:  {".spec.selector"},
+	{Group: "apps", Kind: "DaemonSet"}:   {".spec.selector"},
+	{Group: "apps", Kind: "ReplicaSet"}:  {".spec.selector"},
+	{Group: "apps", Kind: "StatefulSet"}: {".spec.selector", ".spec.serviceName", ".spec.podManagementPolicy", ".spec.volumeClaimTemplates"},
+	{Group: "batch", Kind: "Job"}:        {".spec.selector", ".spec.template", ".spec.completionMode"},
+	{Group: "", Kind: "Service"}:         {".spec.clusterIP", ".spec.clusterIPs"},
+	{Group: "", Kind: "PersistentVolumeClaim"}: {
+		".spec.accessModes", ".spec.selector", ".spec.storageClassName", ".spec.volumeMode", ".spec.volumeName", ".spec.dataSource", ".spec.dataSourceRef",
+	},
+	{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"}:        {".roleRef"},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}: {".roleRef"},
+	{Group: "storage.k8s.io", Kind: "StorageClass"}:                  {".provisioner", ".parameters", ".reclaimPolicy", ".volumeBindingMode"},
+	{Group: "storage.k8s.io", Kind: "CSIDriver"}:                     {".spec.attachRequired", ".spec.podInfoOnMount"},
+}
+
+// immutableWhenMarked tracks the fields of each GroupKind that are immutable if the object sets .immutable to true
+var immutableWhenMarked = map[schema.GroupKind][]string{
+	{Group: "", Kind: "ConfigMap"}: {".data", ".binaryData"},
+	{Group: "", Kind: "Secret"}:    {".data", ".stringData", ".type"},
+}
+
+// ImmutableFieldChanges returns a description of every change in the diff that modifies an immutable field
+func ImmutableFieldChanges(diff *Diff) []string {
+	if diff == nil {
+		return nil
+	}
+	var changes []string
+	for _, objDiff := range diff.Changed {
+		immutableFields := getImmutableFields(objDiff.GroupVersionKind.GroupKind(), objDiff.Before)
+		for _, fieldDiff := range objDiff.Fields {
+			for _, immutableField := range immutableFields {
+				if !isWithinField(fieldDiff.Path, immutableField) {
+					continue
+				}
+				changes = append(changes, fmt.Sprintf("%s %s modifies immutable field %s at %s: before %s, after %s",
+					objDiff.GroupVersionKind.Kind, objDiff.Key, immutableField, fieldDiff.Path, ToJSON(fieldDiff.Before), ToJSON(fieldDiff.After),
+				))
+				break
+			}
+		}
+	}
+	return changes
+}
+
+// CheckImmutableFields fails the test for every change in the diff that modifies an immutable field
+func CheckImmutableFields(t *testing.T, diff *Diff) {
+	for _, change := range ImmutableFieldChanges(diff) {
+		t.Error(change)
+	}
+}
+
+func getImmutableFields(gk schema.GroupKind, before *unstructured.Unstructured) []string {
+	fields := append([]string{}, ImmutableFields[gk]...)
+	markedFields, ok := immutableWhenMarked[gk]
+	if !ok || before == nil {
+		return fields
+	}
+	if immutable, _, _ := unstructured.NestedBool(before.Object, "immutable"); immutable {
+		fields = append(fields, markedFields...)
+		fields = append(fields, ".immutable")
+	}
+	return fields
+}
+
+func isWithinField(path, field string) bool {
+	return path == field || strings.HasPrefix(path, field+".") || strings.HasPrefix(path, field+"[")
+}
@@ -0,0 +1,266 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/rancher/hull/pkg/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImmutableFieldChanges(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Before        string
+		After         string
+		ExpectChanges int
+	}{
+		{
+			Name: "Deployment Replicas",
+			Before: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hello
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: hello
+`,
+			After: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hello
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: hello
+`,
+			ExpectChanges: 0,
+		},
+		{
+			Name: "Deployment Selector",
+			Before: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hello
+spec:
+  selector:
+    matchLabels:
+      app: hello
+`,
+			After: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hello
+spec:
+  selector:
+    matchLabels:
+      app: hello
+      component: world
+`,
+			ExpectChanges: 1,
+		},
+		{
+			Name: "StatefulSet VolumeClaimTemplates",
+			Before: `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: hello
+spec:
+  volumeClaimTemplates:
+  - metadata:
+      name: data
+    spec:
+      resources:
+        requests:
+          storage: 1Gi
+`,
+			After: `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: hello
+spec:
+  volumeClaimTemplates:
+  - metadata:
+      name: data
+    spec:
+      resources:
+        requests:
+          storage: 2Gi
+`,
+			ExpectChanges: 1,
+		},
+		{
+			Name: "Service ClusterIP",
+			Before: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: hello
+spec:
+  clusterIP: None
+  ports:
+  - port: 80
+`,
+			After: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: hello
+spec:
+  ports:
+  - port: 8080
+`,
+			ExpectChanges: 1,
+		},
+		{
+			Name: "Job Template",
+			Before: `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: hello
+spec:
+  template:
+    spec:
+      containers:
+      - name: hello
+        image: hello:v1
+`,
+			After: `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: hello
+spec:
+  template:
+    spec:
+      containers:
+      - name: hello
+        image: hello:v2
+`,
+			ExpectChanges: 1,
+		},
+		{
+			Name: "Mutable ConfigMap",
+			Before: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: hello
+data:
+  hello: world
+`,
+			After: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: hello
+data:
+  hello: rancher
+`,
+			ExpectChanges: 0,
+		},
+		{
+			Name: "Immutable ConfigMap",
+			Before: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: hello
+immutable: true
+data:
+  hello: world
+`,
+			After: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: hello
+data:
+  hello: rancher
+`,
+			ExpectChanges: 2,
+		},
+		{
+			Name: "RoleBinding RoleRef",
+			Before: `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: hello
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: hello
+`,
+			After: `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: hello
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: hello
+`,
+			ExpectChanges: 1,
+		},
+		{
+			Name: "Recreated With New API Version",
+			Before: `
+apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+metadata:
+  name: hello
+spec:
+  minAvailable: 1
+`,
+			After: `
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: hello
+spec:
+  maxUnavailable: 1
+`,
+			ExpectChanges: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			before, err := parser.Parse(tc.Before)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			after, err := parser.Parse(tc.After)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			diff, err := DiffObjectSets(before, after)
+			if !assert.NoError(t, err) {
+				return
+			}
+			changes := ImmutableFieldChanges(diff)
+			assert.Len(t, changes, tc.ExpectChanges, "found changes: %v", changes)
+
+			fakeT := &testing.T{}
+			CheckImmutableFields(fakeT, diff)
+			assert.Equal(t, tc.ExpectChanges > 0, fakeT.Failed())
+		})
+	}
+
+	t.Run("Nil Diff", func(t *testing.T) {
+		assert.Empty(t, ImmutableFieldChanges(nil))
+	})
+}
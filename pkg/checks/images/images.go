@@ -0,0 +1,130 @@
+package images
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/distribution/reference"
+	"github.com/rancher/hull/pkg/checker"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Image is a parsed container image reference
+type Image struct {
+	// Reference is the image exactly as it appears in the container
+	Reference string
+	// Registry is the registry the image is pulled from (docker.io if the reference does not include one)
+	Registry string
+	// Repository is the path of the image within the registry, i.e. rancher/hull or library/nginx
+	Repository string
+	// Tag is the tag of the image, if any
+	Tag string
+	// Digest is the digest of the image, if any
+	Digest string
+}
+
+// ParseImage parses an image reference as the container runtime would, normalizing references without a registry to docker.io
+func ParseImage(ref string) (Image, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return Image{}, fmt.Errorf("invalid image reference %q: %s", ref, err)
+	}
+	image := Image{
+		Reference:  ref,
+		Registry:   reference.Domain(named),
+		Repository: reference.Path(named),
+	}
+	if tagged, ok := named.(reference.Tagged); ok {
+		image.Tag = tagged.Tag()
+	}
+	if digested, ok := named.(reference.Digested); ok {
+		image.Digest = digested.Digest().String()
+	}
+	return image, nil
+}
+
+// Name returns the registry and repository of the image, i.e. docker.io/rancher/hull
+func (i Image) Name() string {
+	return i.Registry + "/" + i.Repository
+}
+
+// Matches returns whether both images refer to the same repository and share a digest or, if either lacks a digest, a tag
+func (i Image) Matches(other Image) bool {
+	if i.Name() != other.Name() {
+		return false
+	}
+	if len(i.Digest) > 0 && len(other.Digest) > 0 {
+		return i.Digest == other.Digest
+	}
+	return len(i.Tag) > 0 && i.Tag == other.Tag
+}
+
+// ContainerImage is the image used by a container or initContainer of a workload
+type ContainerImage struct {
+	Image
+
+	// Workload is the object whose pod template contains the container
+	Workload metav1.Object
+	// Container is the name of the container
+	Container string
+	// InitContainer is whether the container is an initContainer
+	InitContainer bool
+}
+
+func (c ContainerImage) String() string {
+	containerType := "container"
+	if c.InitContainer {
+		containerType = "initContainer"
+	}
+	return fmt.Sprintf("%s %s in %T %s", containerType, c.Container, c.Workload, checker.Key(c.Workload))
+}
+
+// Inventory returns the images of every container and initContainer of the provided pod templates, sorted by workload,
+// along with an error for each image reference that could not be parsed
+func Inventory(podTemplateSpecs map[metav1.Object]corev1.PodTemplateSpec) ([]ContainerImage, []error) {
+	var images []ContainerImage
+	var errs []error
+	for obj, podTemplateSpec := range podTemplateSpecs {
+		for i, containers := range [][]corev1.Container{podTemplateSpec.Spec.InitContainers, podTemplateSpec.Spec.Containers} {
+			for _, container := range containers {
+				containerImage := ContainerImage{
+					Workload:      obj,
+					Container:     container.Name,
+					InitContainer: i == 0,
+				}
+				image, err := ParseImage(container.Image)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: %s", containerImage, err))
+					continue
+				}
+				containerImage.Image = image
+				images = append(images, containerImage)
+			}
+		}
+	}
+	sort.SliceStable(images, func(i, j int) bool {
+		return workloadSortKey(images[i].Workload) < workloadSortKey(images[j].Workload)
+	})
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Error() < errs[j].Error()
+	})
+	return images, errs
+}
+
+func workloadSortKey(obj metav1.Object) string {
+	return fmt.Sprintf("%s %T", checker.Key(obj), obj)
+}
+
+// OnImages runs the check against the images of every container and initContainer of every workload, failing on
+// images that cannot be parsed
+func OnImages(typedCheckFunc func(tc *checker.TestContext, images []ContainerImage)) checker.ChainedCheckFunc {
+	return checker.OnWorkloads(func(tc *checker.TestContext, podTemplateSpecs map[metav1.Object]corev1.PodTemplateSpec) {
+		images, errs := Inventory(podTemplateSpecs)
+		for _, err := range errs {
+			tc.T.Error(err)
+		}
+		typedCheckFunc(tc, images)
+	})
+}
@@ -0,0 +1,126 @@
+package images
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testDigest = "sha256:9ae97d36d26566ff84e8893c64a6dc4fe8ca6d1144bf5b87b2b85a32def253c7"
+
+func TestParseImage(t *testing.T) {
+	testCases := []struct {
+		Name             string
+		Reference        string
+		ShouldThrowError bool
+		Expect           Image
+	}{
+		{
+			Name:      "Official Image Without Tag",
+			Reference: "busybox",
+			Expect:    Image{Reference: "busybox", Registry: "docker.io", Repository: "library/busybox"},
+		},
+		{
+			Name:      "Tagged Image",
+			Reference: "rancher/hull:v0.1.0",
+			Expect:    Image{Reference: "rancher/hull:v0.1.0", Registry: "docker.io", Repository: "rancher/hull", Tag: "v0.1.0"},
+		},
+		{
+			Name:      "Registry With Port",
+			Reference: "registry.example.com:5000/rancher/hull:latest",
+			Expect:    Image{Reference: "registry.example.com:5000/rancher/hull:latest", Registry: "registry.example.com:5000", Repository: "rancher/hull", Tag: "latest"},
+		},
+		{
+			Name:      "Tag And Digest",
+			Reference: "rancher/hull:v0.1.0@" + testDigest,
+			Expect:    Image{Reference: "rancher/hull:v0.1.0@" + testDigest, Registry: "docker.io", Repository: "rancher/hull", Tag: "v0.1.0", Digest: testDigest},
+		},
+		{
+			Name:             "Empty Reference",
+			Reference:        "",
+			ShouldThrowError: true,
+		},
+		{
+			Name:             "Uppercase Repository",
+			Reference:        "Rancher/Hull",
+			ShouldThrowError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			image, err := ParseImage(tc.Reference)
+			if tc.ShouldThrowError {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.Expect, image)
+		})
+	}
+}
+
+func TestImageMatches(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		A      string
+		B      string
+		Expect bool
+	}{
+		{Name: "Same Tag With Normalized Registry", A: "rancher/hull:v0.1.0", B: "docker.io/rancher/hull:v0.1.0", Expect: true},
+		{Name: "Different Tag", A: "rancher/hull:v0.1.0", B: "rancher/hull:v0.2.0"},
+		{Name: "Different Registry", A: "rancher/hull:v0.1.0", B: "registry.example.com/rancher/hull:v0.1.0"},
+		{Name: "Digest Without Tag", A: "rancher/hull@" + testDigest, B: "rancher/hull:v0.1.0@" + testDigest, Expect: true},
+		{Name: "Untagged", A: "rancher/hull", B: "rancher/hull"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			a, err := ParseImage(tc.A)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := ParseImage(tc.B)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.Expect, a.Matches(b))
+			assert.Equal(t, tc.Expect, b.Matches(a))
+		})
+	}
+}
+
+func TestInventory(t *testing.T) {
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "hull"}}
+	daemonSet := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "hull"}}
+	podTemplateSpecs := map[metav1.Object]corev1.PodTemplateSpec{
+		deployment: {
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init", Image: "busybox:1.36"}},
+				Containers:     []corev1.Container{{Name: "app", Image: "rancher/hull:v0.1.0"}, {Name: "invalid", Image: "Invalid"}},
+			},
+		},
+		daemonSet: {
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "agent", Image: "rancher/hull-agent@" + testDigest}},
+			},
+		},
+	}
+	images, errs := Inventory(podTemplateSpecs)
+	var summary []string
+	for _, image := range images {
+		summary = append(summary, image.String()+": "+image.Reference)
+	}
+	assert.Equal(t, []string{
+		"container agent in *v1.DaemonSet {hull a}: rancher/hull-agent@" + testDigest,
+		"initContainer init in *v1.Deployment {hull b}: busybox:1.36",
+		"container app in *v1.Deployment {hull b}: rancher/hull:v0.1.0",
+	}, summary)
+	if assert.Len(t, errs, 1) {
+		assert.Contains(t, errs[0].Error(), "container invalid in *v1.Deployment {hull b}")
+	}
+}
@@ -0,0 +1,117 @@
+package images
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/rancher/hull/pkg/chart"
+	"github.com/rancher/hull/pkg/checker"
+	"github.com/rancher/hull/pkg/test"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+)
+
+// SystemDefaultRegistryValue is the value that Rancher sets to the registry that charts must pull images from
+const SystemDefaultRegistryValue = "global.cattle.systemDefaultRegistry"
+
+// Policy is a set of rules that every image reference must follow
+type Policy struct {
+	// Registry is the registry that every image must be pulled from, i.e. registry.rancher.com, optionally followed
+	// by the path that images are mirrored under, i.e. harbor.example.com/rancher-mirror; images without a registry
+	// are pulled from docker.io
+	Registry string
+	// AllowLatest allows images that are tagged latest or are not tagged
+	AllowLatest bool
+	// RequireDigest requires every image to be pinned to a digest
+	RequireDigest bool
+	// Exempt are the workloads that the policy does not apply to, keyed by checker.Key
+	Exempt []relatedresource.Key
+}
+
+// Violations returns each rule of the policy that the image breaks
+func (p Policy) Violations(image Image) []string {
+	var violations []string
+	if len(p.Registry) > 0 {
+		if registry := strings.TrimSuffix(p.Registry, "/"); !strings.HasPrefix(image.Name(), registry+"/") {
+			violations = append(violations, fmt.Sprintf("expected image to be pulled from registry %s, found %s", registry, image.Name()))
+		}
+	}
+	if !p.AllowLatest {
+		switch {
+		case image.Tag == "latest":
+			violations = append(violations, "image must not use the latest tag")
+		case len(image.Tag) == 0 && len(image.Digest) == 0:
+			violations = append(violations, "image must be tagged")
+		}
+	}
+	if p.RequireDigest && len(image.Digest) == 0 {
+		violations = append(violations, "image must be pinned to a digest")
+	}
+	return violations
+}
+
+// Checks returns checks that fail on every image of a workload that violates the policy
+func (p Policy) Checks() test.Checks {
+	return test.Checks{p.check("")}
+}
+
+func (p Policy) check(prefix string) checker.ChainedCheckFunc {
+	return OnImages(func(tc *checker.TestContext, images []ContainerImage) {
+		for _, image := range images {
+			if checker.IsExempt(p.Exempt, checker.Key(image.Workload)) {
+				continue
+			}
+			for _, violation := range p.Violations(image.Image) {
+				tc.T.Errorf("%s%s uses image %s: %s", prefix, image, image.Reference, violation)
+			}
+		}
+	})
+}
+
+// CheckPolicy checks that every image of every workload rendered by the template follows the policy, reporting the
+// template file and workload of each image that does not
+func CheckPolicy(t *testing.T, template chart.Template, policy Policy) {
+	objectSets := template.GetObjectSets()
+	var templateFiles []string
+	for templateFile, os := range objectSets {
+		if len(templateFile) == 0 || os.Len() == 0 {
+			continue
+		}
+		templateFiles = append(templateFiles, templateFile)
+	}
+	sort.Strings(templateFiles)
+	for _, templateFile := range templateFiles {
+		c, err := checker.NewCheckerFromObjectSet(objectSets[templateFile], templateFile)
+		if err != nil {
+			t.Errorf("unable to check images in %s: %s", templateFile, err)
+			continue
+		}
+		c.Check(t, checker.NewCheckFunc(policy.check(templateFile+": ")))
+	}
+}
+
+// CheckRegistryValue renders the chart with the value at path set to registry and checks that every image of every
+// workload is pulled from that registry
+func CheckRegistryValue(t *testing.T, c chart.Chart, opts *chart.TemplateOptions, path, registry string) {
+	if opts == nil {
+		opts = chart.NewTemplateOptions(c.GetHelmChart().Name(), "default")
+	}
+	templateOptions := *opts
+	templateOptions.Values = opts.Values.MergeValues(chart.NewValues().Set(path, registry))
+	template, err := c.RenderTemplate(&templateOptions)
+	if err != nil {
+		t.Errorf("unable to render chart with %s=%s: %s", path, registry, err)
+		return
+	}
+	CheckPolicy(t, template, Policy{
+		Registry:    registry,
+		AllowLatest: true,
+	})
+}
+
+// CheckSystemDefaultRegistry renders the chart with global.cattle.systemDefaultRegistry set to registry and checks that
+// every image of every workload is pulled from that registry
+func CheckSystemDefaultRegistry(t *testing.T, c chart.Chart, opts *chart.TemplateOptions, registry string) {
+	CheckRegistryValue(t, c, opts, SystemDefaultRegistryValue, registry)
+}
@@ -0,0 +1,184 @@
+package images
+
+import (
+	"testing"
+
+	"github.com/rancher/hull/pkg/chart"
+	"github.com/rancher/hull/pkg/utils"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	imagePolicyChartPath = utils.MustGetPathFromModuleRoot("testdata", "charts", "image-policy-chart")
+)
+
+func TestPolicyViolations(t *testing.T) {
+	testCases := []struct {
+		Name             string
+		Policy           Policy
+		Image            string
+		ExpectViolations []string
+	}{
+		{
+			Name:   "Tagged Image",
+			Policy: Policy{},
+			Image:  "rancher/hull:v0.1.0",
+		},
+		{
+			Name:             "Latest Tag",
+			Policy:           Policy{},
+			Image:            "rancher/hull:latest",
+			ExpectViolations: []string{"image must not use the latest tag"},
+		},
+		{
+			Name:             "Untagged",
+			Policy:           Policy{},
+			Image:            "rancher/hull",
+			ExpectViolations: []string{"image must be tagged"},
+		},
+		{
+			Name:   "Allow Latest",
+			Policy: Policy{AllowLatest: true},
+			Image:  "rancher/hull",
+		},
+		{
+			Name:   "Digest Without Tag",
+			Policy: Policy{RequireDigest: true},
+			Image:  "rancher/hull@" + testDigest,
+		},
+		{
+			Name:             "Missing Digest",
+			Policy:           Policy{RequireDigest: true},
+			Image:            "rancher/hull:v0.1.0",
+			ExpectViolations: []string{"image must be pinned to a digest"},
+		},
+		{
+			Name:   "Registry With Trailing Slash",
+			Policy: Policy{Registry: "registry.rancher.com/"},
+			Image:  "registry.rancher.com/rancher/hull:v0.1.0",
+		},
+		{
+			Name:   "Default Registry",
+			Policy: Policy{Registry: "docker.io"},
+			Image:  "rancher/hull:v0.1.0",
+		},
+		{
+			Name:             "Wrong Registry",
+			Policy:           Policy{Registry: "registry.rancher.com"},
+			Image:            "rancher/hull:latest",
+			ExpectViolations: []string{"expected image to be pulled from registry registry.rancher.com, found docker.io/rancher/hull", "image must not use the latest tag"},
+		},
+		{
+			Name:   "Registry With Path",
+			Policy: Policy{Registry: "harbor.example.com/rancher-mirror"},
+			Image:  "harbor.example.com/rancher-mirror/rancher/hull:v0.1.0",
+		},
+		{
+			Name:             "Wrong Registry Path",
+			Policy:           Policy{Registry: "harbor.example.com/rancher-mirror"},
+			Image:            "harbor.example.com/rancher-mirror-old/rancher/hull:v0.1.0",
+			ExpectViolations: []string{"expected image to be pulled from registry harbor.example.com/rancher-mirror, found harbor.example.com/rancher-mirror-old/rancher/hull"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			image, err := ParseImage(tc.Image)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.ExpectViolations, tc.Policy.Violations(image))
+		})
+	}
+}
+
+func TestCheckPolicy(t *testing.T) {
+	testCases := []struct {
+		Name            string
+		TemplateOptions *chart.TemplateOptions
+		Policy          Policy
+		ExpectFailure   bool
+	}{
+		{
+			Name:            "Latest And Untagged Images",
+			TemplateOptions: chart.NewTemplateOptions("image-policy-chart", "default"),
+			Policy:          Policy{},
+			ExpectFailure:   true,
+		},
+		{
+			Name:            "Exempt Workload",
+			TemplateOptions: chart.NewTemplateOptions("image-policy-chart", "default"),
+			Policy:          Policy{Exempt: []relatedresource.Key{relatedresource.NewKey("default", "image-policy-agent")}},
+		},
+		{
+			Name:            "Tagged Images",
+			TemplateOptions: chart.NewTemplateOptions("image-policy-chart", "default").Set("agent.enabled", false),
+			Policy:          Policy{Registry: "docker.io"},
+		},
+		{
+			Name:            "Missing Digest",
+			TemplateOptions: chart.NewTemplateOptions("image-policy-chart", "default").Set("agent.enabled", false),
+			Policy:          Policy{RequireDigest: true},
+			ExpectFailure:   true,
+		},
+		{
+			Name:            "Pinned To Digest",
+			TemplateOptions: chart.NewTemplateOptions("image-policy-chart", "default").Set("agent.enabled", false).Set("image.digest", testDigest),
+			Policy:          Policy{RequireDigest: true},
+		},
+	}
+
+	c, err := chart.NewChart(imagePolicyChartPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			template, err := c.RenderTemplate(tc.TemplateOptions)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fakeT := &testing.T{}
+			CheckPolicy(fakeT, template, tc.Policy)
+			assert.Equal(t, tc.ExpectFailure, fakeT.Failed())
+		})
+	}
+}
+
+func TestCheckSystemDefaultRegistry(t *testing.T) {
+	testCases := []struct {
+		Name            string
+		TemplateOptions *chart.TemplateOptions
+		Registry        string
+		ExpectFailure   bool
+	}{
+		{
+			Name:          "Hardcoded Image Is Not Rewritten",
+			Registry:      "registry.rancher.com",
+			ExpectFailure: true,
+		},
+		{
+			Name:            "All Images Are Rewritten",
+			TemplateOptions: chart.NewTemplateOptions("image-policy-chart", "default").Set("agent.enabled", false),
+			Registry:        "registry.rancher.com",
+		},
+		{
+			Name:            "All Images Are Rewritten To Registry With Path",
+			TemplateOptions: chart.NewTemplateOptions("image-policy-chart", "default").Set("agent.enabled", false),
+			Registry:        "harbor.example.com/rancher-mirror",
+		},
+	}
+
+	c, err := chart.NewChart(imagePolicyChartPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			fakeT := &testing.T{}
+			CheckSystemDefaultRegistry(fakeT, c, tc.TemplateOptions, tc.Registry)
+			assert.Equal(t, tc.ExpectFailure, fakeT.Failed())
+		})
+	}
+}
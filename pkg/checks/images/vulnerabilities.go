@@ -0,0 +1,350 @@
+package images
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rancher/hull/pkg/checker"
+	"github.com/rancher/hull/pkg/test"
+)
+
+var defaultSeverities = []string{"CRITICAL", "HIGH"}
+
+// Vulnerability is a vulnerability found in an image
+type Vulnerability struct {
+	ID               string
+	Package          string
+	InstalledVersion string
+	// Severity is one of CRITICAL, HIGH, MEDIUM, LOW, or UNKNOWN
+	Severity string
+}
+
+func (v Vulnerability) String() string {
+	if len(v.Package) == 0 {
+		return fmt.Sprintf("%s (%s)", v.ID, v.Severity)
+	}
+	return fmt.Sprintf("%s (%s) in %s %s", v.ID, v.Severity, v.Package, v.InstalledVersion)
+}
+
+// VulnerabilityReport is the result of scanning an image for vulnerabilities
+type VulnerabilityReport struct {
+	// Images are the references to the scanned image, i.e. the reference that was scanned and its repo digests
+	Images          []Image
+	Vulnerabilities []Vulnerability
+}
+
+// Matches returns whether the report is for the image
+func (r VulnerabilityReport) Matches(image Image) bool {
+	for _, reportImage := range r.Images {
+		if reportImage.Matches(image) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadVulnerabilityReports loads the provided Trivy JSON reports, Grype JSON reports, or CycloneDX JSON SBOMs that
+// contain vulnerabilities (i.e. the output of `trivy image --format cyclonedx --scanners vuln`)
+func LoadVulnerabilityReports(paths ...string) ([]VulnerabilityReport, error) {
+	var reports []VulnerabilityReport
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read vulnerability report %s: %s", path, err)
+		}
+		report, err := ParseVulnerabilityReport(data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse vulnerability report %s: %s", path, err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+type trivyReport struct {
+	ArtifactName string `json:"ArtifactName"`
+	Metadata     struct {
+		RepoTags    []string `json:"RepoTags"`
+		RepoDigests []string `json:"RepoDigests"`
+	} `json:"Metadata"`
+	Results []struct {
+		Vulnerabilities []struct {
+			VulnerabilityID  string `json:"VulnerabilityID"`
+			PkgName          string `json:"PkgName"`
+			InstalledVersion string `json:"InstalledVersion"`
+			Severity         string `json:"Severity"`
+		} `json:"Vulnerabilities"`
+	} `json:"Results"`
+}
+
+type grypeReport struct {
+	Matches []struct {
+		Vulnerability struct {
+			ID       string `json:"id"`
+			Severity string `json:"severity"`
+		} `json:"vulnerability"`
+		Artifact struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"artifact"`
+	} `json:"matches"`
+	Source struct {
+		Type   string `json:"type"`
+		Target struct {
+			UserInput   string   `json:"userInput"`
+			Tags        []string `json:"tags"`
+			RepoDigests []string `json:"repoDigests"`
+		} `json:"target"`
+	} `json:"source"`
+}
+
+type cycloneDXComponent struct {
+	BOMRef     string `json:"bom-ref"`
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	Properties []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"properties"`
+}
+
+type cycloneDXBOM struct {
+	BOMFormat string `json:"bomFormat"`
+	Metadata  struct {
+		Component cycloneDXComponent `json:"component"`
+	} `json:"metadata"`
+	Components      []cycloneDXComponent `json:"components"`
+	Vulnerabilities []struct {
+		ID      string `json:"id"`
+		Ratings []struct {
+			Severity string `json:"severity"`
+		} `json:"ratings"`
+		Affects []struct {
+			Ref string `json:"ref"`
+		} `json:"affects"`
+	} `json:"vulnerabilities"`
+}
+
+// ParseVulnerabilityReport parses a Trivy JSON report, Grype JSON report, or CycloneDX JSON SBOM for a single image
+func ParseVulnerabilityReport(data []byte) (VulnerabilityReport, error) {
+	var format struct {
+		ArtifactName string          `json:"ArtifactName"`
+		Matches      json.RawMessage `json:"matches"`
+		BOMFormat    string          `json:"bomFormat"`
+	}
+	if err := json.Unmarshal(data, &format); err != nil {
+		return VulnerabilityReport{}, err
+	}
+	switch {
+	case len(format.ArtifactName) > 0:
+		var r trivyReport
+		if err := json.Unmarshal(data, &r); err != nil {
+			return VulnerabilityReport{}, err
+		}
+		return parseTrivyReport(r)
+	case len(format.Matches) > 0:
+		var r grypeReport
+		if err := json.Unmarshal(data, &r); err != nil {
+			return VulnerabilityReport{}, err
+		}
+		return parseGrypeReport(r)
+	case format.BOMFormat == "CycloneDX":
+		var r cycloneDXBOM
+		if err := json.Unmarshal(data, &r); err != nil {
+			return VulnerabilityReport{}, err
+		}
+		return parseCycloneDXBOM(r)
+	}
+	return VulnerabilityReport{}, fmt.Errorf("unknown format: expected a Trivy JSON report, a Grype JSON report, or a CycloneDX JSON SBOM")
+}
+
+func parseTrivyReport(r trivyReport) (VulnerabilityReport, error) {
+	images, err := parseReportImages(append(append([]string{r.ArtifactName}, r.Metadata.RepoTags...), r.Metadata.RepoDigests...))
+	if err != nil {
+		return VulnerabilityReport{}, err
+	}
+	report := VulnerabilityReport{Images: images}
+	for _, result := range r.Results {
+		for _, v := range result.Vulnerabilities {
+			report.Vulnerabilities = append(report.Vulnerabilities, Vulnerability{
+				ID:               v.VulnerabilityID,
+				Package:          v.PkgName,
+				InstalledVersion: v.InstalledVersion,
+				Severity:         normalizeSeverity(v.Severity),
+			})
+		}
+	}
+	return report, nil
+}
+
+func parseGrypeReport(r grypeReport) (VulnerabilityReport, error) {
+	if r.Source.Type != "image" {
+		return VulnerabilityReport{}, fmt.Errorf("expected a report for an image, found a report for a %s", r.Source.Type)
+	}
+	images, err := parseReportImages(append(append([]string{r.Source.Target.UserInput}, r.Source.Target.Tags...), r.Source.Target.RepoDigests...))
+	if err != nil {
+		return VulnerabilityReport{}, err
+	}
+	report := VulnerabilityReport{Images: images}
+	for _, m := range r.Matches {
+		report.Vulnerabilities = append(report.Vulnerabilities, Vulnerability{
+			ID:               m.Vulnerability.ID,
+			Package:          m.Artifact.Name,
+			InstalledVersion: m.Artifact.Version,
+			Severity:         normalizeSeverity(m.Vulnerability.Severity),
+		})
+	}
+	return report, nil
+}
+
+func parseCycloneDXBOM(r cycloneDXBOM) (VulnerabilityReport, error) {
+	refs := []string{r.Metadata.Component.Name}
+	for _, property := range r.Metadata.Component.Properties {
+		switch property.Name {
+		case "aquasecurity:trivy:RepoTag", "aquasecurity:trivy:RepoDigest":
+			refs = append(refs, property.Value)
+		}
+	}
+	images, err := parseReportImages(refs)
+	if err != nil {
+		return VulnerabilityReport{}, err
+	}
+	components := make(map[string]cycloneDXComponent, len(r.Components))
+	for _, component := range r.Components {
+		components[component.BOMRef] = component
+	}
+	report := VulnerabilityReport{Images: images}
+	for _, v := range r.Vulnerabilities {
+		severity := "UNKNOWN"
+		for _, rating := range v.Ratings {
+			if s := normalizeSeverity(rating.Severity); severityRank(s) > severityRank(severity) {
+				severity = s
+			}
+		}
+		vulnerability := Vulnerability{
+			ID:       v.ID,
+			Severity: severity,
+		}
+		if len(v.Affects) == 0 {
+			report.Vulnerabilities = append(report.Vulnerabilities, vulnerability)
+			continue
+		}
+		for _, affects := range v.Affects {
+			vulnerability.Package = components[affects.Ref].Name
+			vulnerability.InstalledVersion = components[affects.Ref].Version
+			report.Vulnerabilities = append(report.Vulnerabilities, vulnerability)
+		}
+	}
+	return report, nil
+}
+
+func parseReportImages(refs []string) ([]Image, error) {
+	var images []Image
+	for _, ref := range refs {
+		if len(ref) == 0 {
+			continue
+		}
+		image, err := ParseImage(ref)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("report does not identify the scanned image")
+	}
+	return images, nil
+}
+
+func normalizeSeverity(severity string) string {
+	switch s := strings.ToUpper(severity); s {
+	case "CRITICAL", "HIGH", "MEDIUM", "LOW":
+		return s
+	case "NEGLIGIBLE", "INFO", "NONE":
+		return "LOW"
+	}
+	return "UNKNOWN"
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case "CRITICAL":
+		return 4
+	case "HIGH":
+		return 3
+	case "MEDIUM":
+		return 2
+	case "LOW":
+		return 1
+	}
+	return 0
+}
+
+// VulnerabilityOptions configures NoVulnerabilities
+type VulnerabilityOptions struct {
+	// Reports are the vulnerability reports to check images against, i.e. from LoadVulnerabilityReports
+	Reports []VulnerabilityReport
+	// Severities are the severities of vulnerabilities that fail the check (default CRITICAL and HIGH)
+	Severities []string
+	// IgnoreVulnerabilities are the IDs of vulnerabilities that should not fail the check, i.e. CVE-2023-1234
+	IgnoreVulnerabilities []string
+	// FailOnMissingReport fails the check on images that have no report instead of logging a warning
+	FailOnMissingReport bool
+}
+
+// NoVulnerabilities checks that no image of any workload has a vulnerability of the configured severities in the
+// provided reports; no registry or network access is required
+func NoVulnerabilities(opts VulnerabilityOptions) test.Checks {
+	severities := map[string]bool{}
+	for _, severity := range opts.Severities {
+		severities[strings.ToUpper(severity)] = true
+	}
+	if len(severities) == 0 {
+		for _, severity := range defaultSeverities {
+			severities[severity] = true
+		}
+	}
+	ignore := map[string]bool{}
+	for _, id := range opts.IgnoreVulnerabilities {
+		ignore[id] = true
+	}
+	return test.Checks{
+		OnImages(func(tc *checker.TestContext, images []ContainerImage) {
+			for _, image := range images {
+				var reported bool
+				found := map[string]bool{}
+				for _, report := range opts.Reports {
+					if !report.Matches(image.Image) {
+						continue
+					}
+					reported = true
+					for _, v := range report.Vulnerabilities {
+						if severities[v.Severity] && !ignore[v.ID] {
+							found[v.String()] = true
+						}
+					}
+				}
+				if !reported {
+					if opts.FailOnMissingReport {
+						tc.T.Errorf("%s uses image %s, which has no vulnerability report", image, image.Reference)
+					} else {
+						tc.T.Logf("warn: %s uses image %s, which has no vulnerability report", image, image.Reference)
+					}
+					continue
+				}
+				if len(found) == 0 {
+					continue
+				}
+				vulnerabilities := make([]string, 0, len(found))
+				for v := range found {
+					vulnerabilities = append(vulnerabilities, v)
+				}
+				sort.Strings(vulnerabilities)
+				tc.T.Errorf("%s uses image %s, which has %d vulnerabilities:\n%s", image, image.Reference, len(vulnerabilities), strings.Join(vulnerabilities, "\n"))
+			}
+		}),
+	}
+}
@@ -0,0 +1,163 @@
+package images
+
+import (
+	"testing"
+
+	"github.com/rancher/hull/pkg/checker"
+	"github.com/rancher/hull/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	trivyReportPath     = utils.MustGetPathFromModuleRoot("testdata", "vulnerability-reports", "trivy.json")
+	grypeReportPath     = utils.MustGetPathFromModuleRoot("testdata", "vulnerability-reports", "grype.json")
+	cycloneDXReportPath = utils.MustGetPathFromModuleRoot("testdata", "vulnerability-reports", "cyclonedx.json")
+)
+
+func TestLoadVulnerabilityReports(t *testing.T) {
+	testCases := []struct {
+		Name                  string
+		Path                  string
+		ShouldThrowError      bool
+		ExpectImage           string
+		ExpectVulnerabilities []Vulnerability
+	}{
+		{
+			Name:        "Trivy",
+			Path:        trivyReportPath,
+			ExpectImage: "rancher/hull:v0.1.0",
+			ExpectVulnerabilities: []Vulnerability{
+				{ID: "CVE-2023-0001", Package: "libcrypto3", InstalledVersion: "3.1.0-r4", Severity: "HIGH"},
+				{ID: "CVE-2023-0002", Package: "busybox", InstalledVersion: "1.36.0-r9", Severity: "MEDIUM"},
+			},
+		},
+		{
+			Name:        "Grype",
+			Path:        grypeReportPath,
+			ExpectImage: "registry.example.com/rancher/hull-helper:v1.0.0",
+			ExpectVulnerabilities: []Vulnerability{
+				{ID: "CVE-2023-0003", Package: "openssl", InstalledVersion: "1.1.1t", Severity: "CRITICAL"},
+				{ID: "CVE-2023-0004", Package: "zlib", InstalledVersion: "1.2.13", Severity: "LOW"},
+			},
+		},
+		{
+			Name:        "CycloneDX",
+			Path:        cycloneDXReportPath,
+			ExpectImage: "busybox:1.36",
+			ExpectVulnerabilities: []Vulnerability{
+				{ID: "CVE-2023-0005", Package: "busybox", InstalledVersion: "1.36.1-r0", Severity: "HIGH"},
+			},
+		},
+		{
+			Name:             "Not A Report",
+			Path:             utils.MustGetPathFromModuleRoot("testdata", "charts", "simple-chart", "Chart.yaml"),
+			ShouldThrowError: true,
+		},
+		{
+			Name:             "Missing File",
+			Path:             utils.MustGetPathFromModuleRoot("testdata", "vulnerability-reports", "missing.json"),
+			ShouldThrowError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			reports, err := LoadVulnerabilityReports(tc.Path)
+			if tc.ShouldThrowError {
+				assert.NotNil(t, err)
+				return
+			}
+			if !assert.Nil(t, err) || !assert.Len(t, reports, 1) {
+				return
+			}
+			image, err := ParseImage(tc.ExpectImage)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.True(t, reports[0].Matches(image))
+			assert.Equal(t, tc.ExpectVulnerabilities, reports[0].Vulnerabilities)
+		})
+	}
+}
+
+func TestNoVulnerabilities(t *testing.T) {
+	reports, err := LoadVulnerabilityReports(trivyReportPath, grypeReportPath, cycloneDXReportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := func(image string) string {
+		return `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hull
+  namespace: hull
+spec:
+  selector:
+    matchLabels:
+      app: hull
+  template:
+    metadata:
+      labels:
+        app: hull
+    spec:
+      containers:
+      - name: hull
+        image: ` + image + `
+`
+	}
+
+	testCases := []struct {
+		Name          string
+		Image         string
+		Options       VulnerabilityOptions
+		ExpectFailure bool
+	}{
+		{
+			Name:          "High Vulnerability",
+			Image:         "rancher/hull:v0.1.0",
+			Options:       VulnerabilityOptions{Reports: reports},
+			ExpectFailure: true,
+		},
+		{
+			Name:    "Ignored Vulnerability",
+			Image:   "rancher/hull:v0.1.0",
+			Options: VulnerabilityOptions{Reports: reports, IgnoreVulnerabilities: []string{"CVE-2023-0001"}},
+		},
+		{
+			Name:    "Severity Not Configured",
+			Image:   "rancher/hull:v0.1.0",
+			Options: VulnerabilityOptions{Reports: reports, Severities: []string{"critical"}},
+		},
+		{
+			Name:          "Matched By Digest",
+			Image:         "busybox@sha256:9ae97d36d26566ff84e8893c64a6dc4fe8ca6d1144bf5b87b2b85a32def253c7",
+			Options:       VulnerabilityOptions{Reports: reports},
+			ExpectFailure: true,
+		},
+		{
+			Name:    "Missing Report",
+			Image:   "rancher/hull:v0.2.0",
+			Options: VulnerabilityOptions{Reports: reports},
+		},
+		{
+			Name:          "Fail On Missing Report",
+			Image:         "rancher/hull:v0.2.0",
+			Options:       VulnerabilityOptions{Reports: reports, FailOnMissingReport: true},
+			ExpectFailure: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			c, err := checker.NewCheckerFromString(manifest(tc.Image), "deployment.yaml")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			fakeT := &testing.T{}
+			c.Check(fakeT, checker.NewCheckFunc(NoVulnerabilities(tc.Options)...))
+			assert.Equal(t, tc.ExpectFailure, fakeT.Failed())
+		})
+	}
+}
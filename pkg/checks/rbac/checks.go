@@ -0,0 +1,81 @@
+package rbac
+
+import (
+	"github.com/rancher/hull/pkg/checker"
+	"github.com/rancher/hull/pkg/test"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NoWildcardVerbs checks that no rule of a rendered Role or ClusterRole grants every verb
+func NoWildcardVerbs(exempt ...relatedresource.Key) test.Checks {
+	return test.Checks{
+		OnPermissions(func(tc *checker.TestContext, p *Permissions) {
+			for _, role := range p.Roles {
+				checkNoWildcardVerbs(tc, role, role.Rules, exempt)
+			}
+			for _, clusterRole := range p.ClusterRoles {
+				checkNoWildcardVerbs(tc, clusterRole, clusterRole.Rules, exempt)
+			}
+		}),
+	}
+}
+
+func checkNoWildcardVerbs(tc *checker.TestContext, obj metav1.Object, rules []rbacv1.PolicyRule, exempt []relatedresource.Key) {
+	if checker.IsExempt(exempt, checker.Key(obj)) {
+		return
+	}
+	for i, rule := range rules {
+		for _, verb := range rule.Verbs {
+			if verb == rbacv1.VerbAll {
+				tc.T.Errorf("%T %s must not grant all verbs in rules[%d]", obj, checker.Key(obj), i)
+			}
+		}
+	}
+}
+
+// NoClusterWideSecretsAccess checks that no ServiceAccount bound by a rendered RoleBinding or ClusterRoleBinding can
+// get, list, or watch Secrets in every namespace
+//
+// ServiceAccounts that are bound by a ClusterRoleBinding to a ClusterRole whose rules are not known also fail the check,
+// since their access cannot be determined.
+func NoClusterWideSecretsAccess(exempt ...relatedresource.Key) test.Checks {
+	return test.Checks{
+		OnPermissions(func(tc *checker.TestContext, p *Permissions) {
+			for _, serviceAccount := range p.BoundServiceAccounts() {
+				if checker.IsExempt(exempt, serviceAccount) {
+					continue
+				}
+				for _, roleRef := range p.UnknownRoleRefs(serviceAccount, "") {
+					tc.T.Errorf("serviceaccount %s is bound to ClusterRole %s, which is not rendered by the chart, so its access to secrets cannot be checked", serviceAccount, roleRef.Name)
+				}
+				for _, verb := range []string{"get", "list", "watch"} {
+					if p.CanI(serviceAccount, verb, "secrets", "") {
+						tc.T.Errorf("serviceaccount %s must not be able to %s secrets cluster-wide", serviceAccount, verb)
+					}
+				}
+			}
+		}),
+	}
+}
+
+// OnlyChartServiceAccountsAreBound checks that every ServiceAccount bound by a rendered RoleBinding or
+// ClusterRoleBinding is rendered by the chart, so that the chart does not grant permissions to ServiceAccounts it does
+// not own
+func OnlyChartServiceAccountsAreBound(exempt ...relatedresource.Key) test.Checks {
+	return test.Checks{
+		OnPermissions(func(tc *checker.TestContext, p *Permissions) {
+			rendered := map[relatedresource.Key]bool{}
+			for _, serviceAccount := range p.ServiceAccounts {
+				rendered[checker.Key(serviceAccount)] = true
+			}
+			for _, serviceAccount := range p.BoundServiceAccounts() {
+				if !rendered[serviceAccount] && !checker.IsExempt(exempt, serviceAccount) {
+					tc.T.Errorf("serviceaccount %s is bound to a role by the chart but is not rendered by the chart", serviceAccount)
+				}
+			}
+		}),
+	}
+}
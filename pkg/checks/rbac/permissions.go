@@ -0,0 +1,280 @@
+package rbac
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/rancher/hull/pkg/checker"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const aggregateToLabelPrefix = "rbac.authorization.k8s.io/aggregate-to-"
+
+// defaultClusterRoles are the default user-facing ClusterRoles; cluster-admin grants everything and the others are
+// aggregated from other ClusterRoles
+//
+// Only the rules that the chart aggregates into admin, edit, and view are known, since their own rules are not rendered.
+var defaultClusterRoles = []*rbacv1.ClusterRole{
+	{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{rbacv1.APIGroupAll}, Resources: []string{rbacv1.ResourceAll}, Verbs: []string{rbacv1.VerbAll}},
+			{NonResourceURLs: []string{rbacv1.NonResourceAll}, Verbs: []string{rbacv1.VerbAll}},
+		},
+	},
+	{
+		ObjectMeta:      metav1.ObjectMeta{Name: "admin"},
+		AggregationRule: aggregateTo("admin"),
+	},
+	{
+		ObjectMeta:      metav1.ObjectMeta{Name: "edit", Labels: map[string]string{aggregateToLabelPrefix + "admin": "true"}},
+		AggregationRule: aggregateTo("edit"),
+	},
+	{
+		ObjectMeta:      metav1.ObjectMeta{Name: "view", Labels: map[string]string{aggregateToLabelPrefix + "edit": "true"}},
+		AggregationRule: aggregateTo("view"),
+	},
+}
+
+func aggregateTo(name string) *rbacv1.AggregationRule {
+	return &rbacv1.AggregationRule{
+		ClusterRoleSelectors: []metav1.LabelSelector{
+			{MatchLabels: map[string]string{aggregateToLabelPrefix + name: "true"}},
+		},
+	}
+}
+
+// Permissions is the effective set of permissions granted by rendered Roles, ClusterRoles, RoleBindings, and
+// ClusterRoleBindings
+type Permissions struct {
+	ServiceAccounts     []*corev1.ServiceAccount
+	Roles               []*rbacv1.Role
+	ClusterRoles        []*rbacv1.ClusterRole
+	RoleBindings        []*rbacv1.RoleBinding
+	ClusterRoleBindings []*rbacv1.ClusterRoleBinding
+
+	roleRules        map[relatedresource.Key][]rbacv1.PolicyRule
+	clusterRoleRules map[string][]rbacv1.PolicyRule
+}
+
+// NewPermissions resolves the rules of every Role and ClusterRole, including the rules of ClusterRoles that are
+// aggregated via aggregation labels
+func NewPermissions(serviceAccounts []*corev1.ServiceAccount, roles []*rbacv1.Role, clusterRoles []*rbacv1.ClusterRole, roleBindings []*rbacv1.RoleBinding, clusterRoleBindings []*rbacv1.ClusterRoleBinding) *Permissions {
+	p := &Permissions{
+		ServiceAccounts:     serviceAccounts,
+		Roles:               roles,
+		ClusterRoles:        clusterRoles,
+		RoleBindings:        roleBindings,
+		ClusterRoleBindings: clusterRoleBindings,
+		roleRules:           map[relatedresource.Key][]rbacv1.PolicyRule{},
+		clusterRoleRules:    map[string][]rbacv1.PolicyRule{},
+	}
+	for _, role := range roles {
+		p.roleRules[checker.Key(role)] = role.Rules
+	}
+	allClusterRoles := map[string]*rbacv1.ClusterRole{}
+	for _, clusterRole := range defaultClusterRoles {
+		allClusterRoles[clusterRole.Name] = clusterRole
+	}
+	for _, clusterRole := range clusterRoles {
+		allClusterRoles[clusterRole.Name] = clusterRole
+	}
+	for name := range allClusterRoles {
+		p.clusterRoleRules[name] = aggregatedRules(allClusterRoles, name, map[string]bool{})
+	}
+	return p
+}
+
+// aggregatedRules returns the rules of a ClusterRole as they would be set by the aggregation controller
+func aggregatedRules(clusterRoles map[string]*rbacv1.ClusterRole, name string, visited map[string]bool) []rbacv1.PolicyRule {
+	clusterRole, ok := clusterRoles[name]
+	if !ok || visited[name] {
+		return nil
+	}
+	if clusterRole.AggregationRule == nil {
+		return clusterRole.Rules
+	}
+	visited[name] = true
+	var names []string
+	for otherName, other := range clusterRoles {
+		if otherName == name {
+			continue
+		}
+		for _, labelSelector := range clusterRole.AggregationRule.ClusterRoleSelectors {
+			selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+			if err != nil || !selector.Matches(labels.Set(other.Labels)) {
+				continue
+			}
+			names = append(names, otherName)
+			break
+		}
+	}
+	sort.Strings(names)
+	var rules []rbacv1.PolicyRule
+	for _, otherName := range names {
+		rules = append(rules, aggregatedRules(clusterRoles, otherName, visited)...)
+	}
+	return rules
+}
+
+// OnPermissions runs the check against the permissions granted by the rendered RBAC resources
+func OnPermissions(typedCheckFunc func(tc *checker.TestContext, p *Permissions)) checker.ChainedCheckFunc {
+	return func(tc *checker.TestContext) checker.CheckFunc {
+		return func(t *testing.T, objs struct {
+			ServiceAccounts     []*corev1.ServiceAccount
+			Roles               []*rbacv1.Role
+			ClusterRoles        []*rbacv1.ClusterRole
+			RoleBindings        []*rbacv1.RoleBinding
+			ClusterRoleBindings []*rbacv1.ClusterRoleBinding
+		}) {
+			tc.T = t
+			typedCheckFunc(tc, NewPermissions(objs.ServiceAccounts, objs.Roles, objs.ClusterRoles, objs.RoleBindings, objs.ClusterRoleBindings))
+		}
+	}
+}
+
+// RulesFor returns the rules granted to the ServiceAccount in the namespace; if the namespace is empty, only rules that
+// are granted in every namespace by ClusterRoleBindings are returned
+//
+// Roles and ClusterRoles that are neither rendered nor default ClusterRoles grant no rules; see UnknownRoleRefs.
+func (p *Permissions) RulesFor(serviceAccount relatedresource.Key, namespace string) []rbacv1.PolicyRule {
+	var rules []rbacv1.PolicyRule
+	for _, ref := range p.roleRefsFor(serviceAccount, namespace) {
+		roleRules, _ := p.rulesOf(ref)
+		rules = append(rules, roleRules...)
+	}
+	return rules
+}
+
+// UnknownRoleRefs returns the Roles and ClusterRoles bound to the ServiceAccount in the namespace (or, if the namespace
+// is empty, in every namespace) whose rules are not known, since they are neither rendered nor default ClusterRoles
+// (i.e. a system: ClusterRole)
+func (p *Permissions) UnknownRoleRefs(serviceAccount relatedresource.Key, namespace string) []rbacv1.RoleRef {
+	var unknown []rbacv1.RoleRef
+	for _, ref := range p.roleRefsFor(serviceAccount, namespace) {
+		if _, ok := p.rulesOf(ref); !ok {
+			unknown = append(unknown, ref.RoleRef)
+		}
+	}
+	return unknown
+}
+
+// boundRoleRef is the RoleRef of a RoleBinding (in its namespace) or ClusterRoleBinding (with no namespace)
+type boundRoleRef struct {
+	rbacv1.RoleRef
+	namespace string
+}
+
+func (p *Permissions) roleRefsFor(serviceAccount relatedresource.Key, namespace string) []boundRoleRef {
+	var refs []boundRoleRef
+	for _, clusterRoleBinding := range p.ClusterRoleBindings {
+		if bindsServiceAccount(clusterRoleBinding.Subjects, "", serviceAccount) {
+			refs = append(refs, boundRoleRef{RoleRef: clusterRoleBinding.RoleRef})
+		}
+	}
+	if len(namespace) == 0 {
+		return refs
+	}
+	for _, roleBinding := range p.RoleBindings {
+		if roleBinding.Namespace != namespace || !bindsServiceAccount(roleBinding.Subjects, roleBinding.Namespace, serviceAccount) {
+			continue
+		}
+		refs = append(refs, boundRoleRef{RoleRef: roleBinding.RoleRef, namespace: roleBinding.Namespace})
+	}
+	return refs
+}
+
+func (p *Permissions) rulesOf(ref boundRoleRef) ([]rbacv1.PolicyRule, bool) {
+	if ref.Kind == "ClusterRole" {
+		rules, ok := p.clusterRoleRules[ref.Name]
+		return rules, ok
+	}
+	rules, ok := p.roleRules[relatedresource.NewKey(ref.namespace, ref.Name)]
+	return rules, ok
+}
+
+// CanI returns whether the ServiceAccount can perform the verb on the resource in the namespace (or, if the namespace is
+// empty, in every namespace and at the cluster scope)
+//
+// The resource is provided as in `kubectl auth can-i`, i.e. secrets, deployments.apps, or pods/log.
+func (p *Permissions) CanI(serviceAccount relatedresource.Key, verb, resource, namespace string) bool {
+	resourceName, subresource, _ := strings.Cut(resource, "/")
+	resourceName, group, _ := strings.Cut(resourceName, ".")
+	if len(subresource) > 0 {
+		resourceName += "/" + subresource
+	}
+	for _, rule := range p.RulesFor(serviceAccount, namespace) {
+		if len(rule.ResourceNames) > 0 {
+			continue
+		}
+		if matches(rule.Verbs, verb) && matches(rule.APIGroups, group) && matches(rule.Resources, resourceName) {
+			return true
+		}
+	}
+	return false
+}
+
+// BoundServiceAccounts returns every ServiceAccount that is a subject of a rendered RoleBinding or ClusterRoleBinding
+func (p *Permissions) BoundServiceAccounts() []relatedresource.Key {
+	bound := map[relatedresource.Key]bool{}
+	for _, roleBinding := range p.RoleBindings {
+		for _, key := range serviceAccountSubjects(roleBinding.Subjects, roleBinding.Namespace) {
+			bound[key] = true
+		}
+	}
+	for _, clusterRoleBinding := range p.ClusterRoleBindings {
+		for _, key := range serviceAccountSubjects(clusterRoleBinding.Subjects, "") {
+			bound[key] = true
+		}
+	}
+	keys := make([]relatedresource.Key, 0, len(bound))
+	for key := range bound {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Namespace != keys[j].Namespace {
+			return keys[i].Namespace < keys[j].Namespace
+		}
+		return keys[i].Name < keys[j].Name
+	})
+	return keys
+}
+
+func serviceAccountSubjects(subjects []rbacv1.Subject, namespace string) []relatedresource.Key {
+	var keys []relatedresource.Key
+	for _, subject := range subjects {
+		if subject.Kind != rbacv1.ServiceAccountKind {
+			continue
+		}
+		subjectNamespace := subject.Namespace
+		if len(subjectNamespace) == 0 {
+			subjectNamespace = namespace
+		}
+		keys = append(keys, relatedresource.NewKey(subjectNamespace, subject.Name))
+	}
+	return keys
+}
+
+func bindsServiceAccount(subjects []rbacv1.Subject, namespace string, serviceAccount relatedresource.Key) bool {
+	for _, key := range serviceAccountSubjects(subjects, namespace) {
+		if key == serviceAccount {
+			return true
+		}
+	}
+	return false
+}
+
+func matches(values []string, value string) bool {
+	for _, v := range values {
+		if v == rbacv1.ResourceAll || v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,419 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/rancher/hull/pkg/checker"
+	"github.com/rancher/hull/pkg/test"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/stretchr/testify/assert"
+)
+
+const permissionsManifest = `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: hull
+  namespace: hull
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: hull
+aggregationRule:
+  clusterRoleSelectors:
+  - matchLabels:
+      hull.cattle.io/aggregate-to-hull: "true"
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: hull-configmaps
+  labels:
+    hull.cattle.io/aggregate-to-hull: "true"
+rules:
+- apiGroups: [""]
+  resources: ["configmaps"]
+  verbs: ["get", "list", "watch"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: hull-view
+  labels:
+    rbac.authorization.k8s.io/aggregate-to-view: "true"
+rules:
+- apiGroups: ["hull.cattle.io"]
+  resources: ["hulls"]
+  verbs: ["get"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: hull
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: hull
+subjects:
+- kind: ServiceAccount
+  name: hull
+  namespace: hull
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: hull
+  namespace: hull
+rules:
+- apiGroups: [""]
+  resources: ["secrets"]
+  verbs: ["get"]
+- apiGroups: [""]
+  resources: ["pods/log"]
+  verbs: ["get"]
+- apiGroups: ["apps"]
+  resources: ["deployments"]
+  resourceNames: ["hull"]
+  verbs: ["patch"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: hull
+  namespace: hull
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: hull
+subjects:
+- kind: ServiceAccount
+  name: hull
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: hull-view
+  namespace: other
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: view
+subjects:
+- kind: ServiceAccount
+  name: hull
+  namespace: hull
+`
+
+func TestCanI(t *testing.T) {
+	hull := relatedresource.NewKey("hull", "hull")
+
+	testCases := []struct {
+		Name           string
+		ServiceAccount relatedresource.Key
+		Verb           string
+		Resource       string
+		Namespace      string
+		Expected       bool
+	}{
+		{
+			Name:           "Aggregated ClusterRole Cluster-Wide",
+			ServiceAccount: hull,
+			Verb:           "list",
+			Resource:       "configmaps",
+			Expected:       true,
+		},
+		{
+			Name:           "Aggregated ClusterRole In Namespace",
+			ServiceAccount: hull,
+			Verb:           "watch",
+			Resource:       "configmaps",
+			Namespace:      "default",
+			Expected:       true,
+		},
+		{
+			Name:           "Verb Not Granted",
+			ServiceAccount: hull,
+			Verb:           "delete",
+			Resource:       "configmaps",
+		},
+		{
+			Name:           "Role In Namespace",
+			ServiceAccount: hull,
+			Verb:           "get",
+			Resource:       "secrets",
+			Namespace:      "hull",
+			Expected:       true,
+		},
+		{
+			Name:           "Role Not Granted Cluster-Wide",
+			ServiceAccount: hull,
+			Verb:           "get",
+			Resource:       "secrets",
+		},
+		{
+			Name:           "Role Not Granted In Other Namespace",
+			ServiceAccount: hull,
+			Verb:           "get",
+			Resource:       "secrets",
+			Namespace:      "default",
+		},
+		{
+			Name:           "Subresource",
+			ServiceAccount: hull,
+			Verb:           "get",
+			Resource:       "pods/log",
+			Namespace:      "hull",
+			Expected:       true,
+		},
+		{
+			Name:           "Resource Without Subresource",
+			ServiceAccount: hull,
+			Verb:           "get",
+			Resource:       "pods",
+			Namespace:      "hull",
+		},
+		{
+			Name:           "Rule With Resource Names",
+			ServiceAccount: hull,
+			Verb:           "patch",
+			Resource:       "deployments.apps",
+			Namespace:      "hull",
+		},
+		{
+			Name:           "Aggregated Into Default ClusterRole",
+			ServiceAccount: hull,
+			Verb:           "get",
+			Resource:       "hulls.hull.cattle.io",
+			Namespace:      "other",
+			Expected:       true,
+		},
+		{
+			Name:           "Aggregated Into Default ClusterRole In Other Namespace",
+			ServiceAccount: hull,
+			Verb:           "get",
+			Resource:       "hulls.hull.cattle.io",
+			Namespace:      "default",
+		},
+		{
+			Name:           "Unbound ServiceAccount",
+			ServiceAccount: relatedresource.NewKey("hull", "other"),
+			Verb:           "get",
+			Resource:       "configmaps",
+		},
+	}
+
+	c, err := checker.NewCheckerFromString(permissionsManifest, "manifest.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var permissions *Permissions
+	c.Check(t, checker.NewCheckFunc(OnPermissions(func(tc *checker.TestContext, p *Permissions) {
+		permissions = p
+	})))
+	if !assert.NotNil(t, permissions) {
+		return
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, permissions.CanI(tc.ServiceAccount, tc.Verb, tc.Resource, tc.Namespace))
+		})
+	}
+}
+
+func TestChecks(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Checks        test.Checks
+		Manifest      string
+		ExpectFailure bool
+	}{
+		{
+			Name:   "No Wildcard Verbs",
+			Checks: NoWildcardVerbs(),
+		},
+		{
+			Name:   "Wildcard Verbs",
+			Checks: NoWildcardVerbs(),
+			Manifest: `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: wildcard
+  namespace: hull
+rules:
+- apiGroups: [""]
+  resources: ["configmaps"]
+  verbs: ["*"]
+`,
+			ExpectFailure: true,
+		},
+		{
+			Name:   "Exempt Wildcard Verbs",
+			Checks: NoWildcardVerbs(relatedresource.NewKey("hull", "wildcard")),
+			Manifest: `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: wildcard
+  namespace: hull
+rules:
+- apiGroups: [""]
+  resources: ["configmaps"]
+  verbs: ["*"]
+`,
+		},
+		{
+			Name:   "No Cluster-Wide Secrets Access",
+			Checks: NoClusterWideSecretsAccess(),
+		},
+		{
+			Name:   "Cluster-Wide Secrets Access Via Aggregation",
+			Checks: NoClusterWideSecretsAccess(),
+			Manifest: `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: hull-secrets
+  labels:
+    hull.cattle.io/aggregate-to-hull: "true"
+rules:
+- apiGroups: [""]
+  resources: ["secrets"]
+  verbs: ["list"]
+`,
+			ExpectFailure: true,
+		},
+		{
+			Name:   "Cluster-Wide Secrets Access Via cluster-admin",
+			Checks: NoClusterWideSecretsAccess(),
+			Manifest: `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: hull-admin
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: cluster-admin
+subjects:
+- kind: ServiceAccount
+  name: hull
+  namespace: hull
+`,
+			ExpectFailure: true,
+		},
+		{
+			Name:   "Bound To Unknown ClusterRole Cluster-Wide",
+			Checks: NoClusterWideSecretsAccess(),
+			Manifest: `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: hull-auth-delegator
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: system:auth-delegator
+subjects:
+- kind: ServiceAccount
+  name: hull
+  namespace: hull
+`,
+			ExpectFailure: true,
+		},
+		{
+			Name:   "Bound To Unknown ClusterRole In Namespace",
+			Checks: NoClusterWideSecretsAccess(),
+			Manifest: `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: hull-auth-reader
+  namespace: kube-system
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: extension-apiserver-authentication-reader
+subjects:
+- kind: ServiceAccount
+  name: hull
+  namespace: hull
+`,
+		},
+		{
+			Name:   "Exempt Cluster-Wide Secrets Access",
+			Checks: NoClusterWideSecretsAccess(relatedresource.NewKey("hull", "hull")),
+			Manifest: `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: hull-secrets
+  labels:
+    hull.cattle.io/aggregate-to-hull: "true"
+rules:
+- apiGroups: ["*"]
+  resources: ["*"]
+  verbs: ["get"]
+`,
+		},
+		{
+			Name:   "Only Chart ServiceAccounts Are Bound",
+			Checks: OnlyChartServiceAccountsAreBound(),
+		},
+		{
+			Name:   "ServiceAccount Not Rendered By Chart Is Bound",
+			Checks: OnlyChartServiceAccountsAreBound(),
+			Manifest: `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: default
+  namespace: hull
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: hull
+subjects:
+- kind: ServiceAccount
+  name: default
+`,
+			ExpectFailure: true,
+		},
+		{
+			Name:   "Exempt ServiceAccount Not Rendered By Chart Is Bound",
+			Checks: OnlyChartServiceAccountsAreBound(relatedresource.NewKey("hull", "default")),
+			Manifest: `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: default
+  namespace: hull
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: hull
+subjects:
+- kind: ServiceAccount
+  name: default
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			manifest := permissionsManifest
+			if len(tc.Manifest) > 0 {
+				manifest += "---" + tc.Manifest
+			}
+			c, err := checker.NewCheckerFromString(manifest, "manifest.yaml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			fakeT := &testing.T{}
+			c.Check(fakeT, checker.NewCheckFunc(tc.Checks...))
+			assert.Equal(t, tc.ExpectFailure, fakeT.Failed())
+		})
+	}
+}
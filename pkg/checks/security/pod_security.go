@@ -0,0 +1,91 @@
+package security
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/rancher/hull/pkg/checker"
+	"github.com/rancher/hull/pkg/test"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"k8s.io/pod-security-admission/api"
+	"k8s.io/pod-security-admission/policy"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	PodSecurityBaselineCheck   = "Workloads Meet Baseline Pod Security Standard"
+	PodSecurityRestrictedCheck = "Workloads Meet Restricted Pod Security Standard"
+)
+
+var podSecurityEvaluator = mustNewPodSecurityEvaluator()
+
+func mustNewPodSecurityEvaluator() policy.Evaluator {
+	evaluator, err := policy.NewEvaluator(policy.DefaultChecks())
+	if err != nil {
+		panic(err)
+	}
+	return evaluator
+}
+
+// PodSecurityBaseline checks that the pod of every workload would be admitted to a namespace that enforces the baseline
+// Pod Security Standard in the Kubernetes version of the case
+func PodSecurityBaseline(exempt ...relatedresource.Key) test.Checks {
+	return podSecurity(api.LevelBaseline, exempt)
+}
+
+// PodSecurityRestricted checks that the pod of every workload would be admitted to a namespace that enforces the
+// restricted Pod Security Standard in the Kubernetes version of the case
+func PodSecurityRestricted(exempt ...relatedresource.Key) test.Checks {
+	return podSecurity(api.LevelRestricted, exempt)
+}
+
+func podSecurity(level api.Level, exempt []relatedresource.Key) test.Checks {
+	return test.Checks{
+		checker.OnWorkloads(func(tc *checker.TestContext, podTemplateSpecs map[metav1.Object]corev1.PodTemplateSpec) {
+			levelVersion := api.LevelVersion{
+				Level:   level,
+				Version: podSecurityVersion(tc),
+			}
+			for obj, podTemplateSpec := range podTemplateSpecs {
+				if checker.IsExempt(exempt, checker.Key(obj)) {
+					continue
+				}
+				for _, violation := range podSecurityViolations(levelVersion, podTemplateSpec) {
+					tc.T.Errorf("%T %s violates PodSecurity %q: %s", obj, checker.Key(obj), levelVersion, violation)
+				}
+			}
+		}),
+	}
+}
+
+// podSecurityViolations returns the reason for each check of the Pod Security Standards that the pod fails
+func podSecurityViolations(levelVersion api.LevelVersion, podTemplateSpec corev1.PodTemplateSpec) []string {
+	var violations []string
+	for _, result := range podSecurityEvaluator.EvaluatePod(levelVersion, &podTemplateSpec.ObjectMeta, &podTemplateSpec.Spec) {
+		if result.Allowed {
+			continue
+		}
+		if len(result.ForbiddenDetail) > 0 {
+			violations = append(violations, fmt.Sprintf("%s (%s)", result.ForbiddenReason, result.ForbiddenDetail))
+		} else {
+			violations = append(violations, result.ForbiddenReason)
+		}
+	}
+	return violations
+}
+
+// podSecurityVersion returns the version of the Pod Security Standards for the KubeVersion that the case was rendered
+// with or the latest version if it is unknown
+func podSecurityVersion(tc *checker.TestContext) api.Version {
+	kubeVersion, ok := checker.RenderValue[string](tc, ".Capabilities.KubeVersion.Version")
+	if !ok {
+		return api.LatestVersion()
+	}
+	version, err := semver.NewVersion(kubeVersion)
+	if err != nil {
+		return api.LatestVersion()
+	}
+	return api.MajorMinorVersion(int(version.Major()), int(version.Minor()))
+}
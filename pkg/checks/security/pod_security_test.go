@@ -0,0 +1,157 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/rancher/hull/pkg/checker"
+	"github.com/rancher/hull/pkg/test"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/stretchr/testify/assert"
+	helmChartUtil "helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/pod-security-admission/api"
+	"sigs.k8s.io/yaml"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// restrictedManifest meets the restricted Pod Security Standard except that it does not drop ALL capabilities, which
+// is only required from Kubernetes 1.22
+const restrictedManifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: restricted
+  namespace: hull
+spec:
+  selector:
+    matchLabels:
+      app: restricted
+  template:
+    metadata:
+      labels:
+        app: restricted
+    spec:
+      securityContext:
+        runAsNonRoot: true
+        seccompProfile:
+          type: RuntimeDefault
+      containers:
+      - name: restricted
+        image: rancher/hull:latest
+        securityContext:
+          allowPrivilegeEscalation: false
+`
+
+const privilegedManifest = `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: privileged
+  namespace: hull
+spec:
+  selector:
+    matchLabels:
+      app: privileged
+  template:
+    metadata:
+      labels:
+        app: privileged
+    spec:
+      hostNetwork: true
+      containers:
+      - name: privileged
+        image: rancher/hull:latest
+        securityContext:
+          privileged: true
+`
+
+func TestPodSecurity(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Manifest      string
+		KubeVersion   string
+		Checks        test.Checks
+		ExpectFailure bool
+	}{
+		{
+			Name:        "Restricted Workload Meets Baseline",
+			Manifest:    restrictedManifest,
+			KubeVersion: "v1.28.0",
+			Checks:      PodSecurityBaseline(),
+		},
+		{
+			Name:        "Restricted Workload Meets Restricted Before Kubernetes 1.22",
+			Manifest:    restrictedManifest,
+			KubeVersion: "v1.21.0",
+			Checks:      PodSecurityRestricted(),
+		},
+		{
+			Name:          "Restricted Workload Does Not Meet Restricted On Kubernetes 1.22",
+			Manifest:      restrictedManifest,
+			KubeVersion:   "v1.22.0",
+			Checks:        PodSecurityRestricted(),
+			ExpectFailure: true,
+		},
+		{
+			Name:          "Restricted Workload Does Not Meet Restricted On Latest Version",
+			Manifest:      restrictedManifest,
+			Checks:        PodSecurityRestricted(),
+			ExpectFailure: true,
+		},
+		{
+			Name:          "Privileged Workload Does Not Meet Baseline",
+			Manifest:      privilegedManifest,
+			KubeVersion:   "v1.28.0",
+			Checks:        PodSecurityBaseline(),
+			ExpectFailure: true,
+		},
+		{
+			Name:        "Exempt Privileged Workload",
+			Manifest:    privilegedManifest,
+			KubeVersion: "v1.28.0",
+			Checks:      PodSecurityRestricted(relatedresource.NewKey("hull", "privileged")),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			c, err := checker.NewCheckerFromString(tc.Manifest, "manifest.yaml")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			checks := test.Checks{
+				checker.Once(func(tctx *checker.TestContext) {
+					if len(tc.KubeVersion) == 0 {
+						return
+					}
+					capabilities := helmChartUtil.DefaultCapabilities.Copy()
+					capabilities.KubeVersion.Version = tc.KubeVersion
+					tctx.RenderValues = helmChartUtil.Values{"Capabilities": capabilities}
+				}),
+			}
+			fakeT := &testing.T{}
+			c.Check(fakeT, checker.NewCheckFunc(append(checks, tc.Checks...)...))
+			assert.Equal(t, tc.ExpectFailure, fakeT.Failed())
+		})
+	}
+}
+
+func TestPodSecurityViolations(t *testing.T) {
+	var daemonSet struct {
+		Spec struct {
+			Template corev1.PodTemplateSpec `json:"template"`
+		} `json:"spec"`
+	}
+	if err := yaml.Unmarshal([]byte(privilegedManifest), &daemonSet); err != nil {
+		t.Fatal(err)
+	}
+	violations := podSecurityViolations(api.LevelVersion{
+		Level:   api.LevelBaseline,
+		Version: api.MajorMinorVersion(1, 28),
+	}, daemonSet.Spec.Template)
+	assert.Equal(t, []string{
+		"host namespaces (hostNetwork=true)",
+		`privileged (container "privileged" must not set securityContext.privileged=true)`,
+	}, violations)
+}
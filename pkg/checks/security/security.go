@@ -0,0 +1,194 @@
+package security
+
+import (
+	"github.com/rancher/hull/pkg/checker"
+	"github.com/rancher/hull/pkg/test"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	RunAsNonRootCheck                   = "Workloads Run As Non Root"
+	ReadOnlyRootFilesystemCheck         = "Workloads Have Read Only Root Filesystems"
+	DropAllCapabilitiesCheck            = "Workloads Drop All Capabilities"
+	NoPrivilegedContainersCheck         = "Workloads Have No Privileged Containers"
+	NoHostPathVolumesCheck              = "Workloads Have No HostPath Volumes"
+	NoHostNetworkCheck                  = "Workloads Do Not Use Host Network"
+	ResourceRequestsAndLimitsCheck      = "Workloads Have Resource Requests And Limits"
+	SeccompProfileCheck                 = "Workloads Have Seccomp Profiles"
+	NoAutomountServiceAccountTokenCheck = "Workloads Do Not Automount Service Account Tokens"
+)
+
+// Exemptions maps the name of a check to the workloads that are exempt from it, keyed by checker.Key
+type Exemptions map[string][]relatedresource.Key
+
+// NamedChecks returns every check in this package as a NamedCheck that can be added to a test.Suite
+func NamedChecks(exemptions Exemptions) []test.NamedCheck {
+	return []test.NamedCheck{
+		{Name: RunAsNonRootCheck, Checks: RunAsNonRoot(exemptions[RunAsNonRootCheck]...)},
+		{Name: ReadOnlyRootFilesystemCheck, Checks: ReadOnlyRootFilesystem(exemptions[ReadOnlyRootFilesystemCheck]...)},
+		{Name: DropAllCapabilitiesCheck, Checks: DropAllCapabilities(exemptions[DropAllCapabilitiesCheck]...)},
+		{Name: NoPrivilegedContainersCheck, Checks: NoPrivilegedContainers(exemptions[NoPrivilegedContainersCheck]...)},
+		{Name: NoHostPathVolumesCheck, Checks: NoHostPathVolumes(exemptions[NoHostPathVolumesCheck]...)},
+		{Name: NoHostNetworkCheck, Checks: NoHostNetwork(exemptions[NoHostNetworkCheck]...)},
+		{Name: ResourceRequestsAndLimitsCheck, Checks: ResourceRequestsAndLimits(exemptions[ResourceRequestsAndLimitsCheck]...)},
+		{Name: SeccompProfileCheck, Checks: SeccompProfile(exemptions[SeccompProfileCheck]...)},
+		{Name: NoAutomountServiceAccountTokenCheck, Checks: NoAutomountServiceAccountToken(exemptions[NoAutomountServiceAccountTokenCheck]...)},
+	}
+}
+
+// RunAsNonRoot checks that every container of every workload sets runAsNonRoot to true on the container or the pod
+func RunAsNonRoot(exempt ...relatedresource.Key) test.Checks {
+	return perContainer(exempt, func(tc *checker.TestContext, obj metav1.Object, podSpec corev1.PodSpec, container corev1.Container) {
+		runAsNonRoot := container.SecurityContext != nil && container.SecurityContext.RunAsNonRoot != nil && *container.SecurityContext.RunAsNonRoot
+		if container.SecurityContext == nil || container.SecurityContext.RunAsNonRoot == nil {
+			runAsNonRoot = podSpec.SecurityContext != nil && podSpec.SecurityContext.RunAsNonRoot != nil && *podSpec.SecurityContext.RunAsNonRoot
+		}
+		if !runAsNonRoot {
+			tc.T.Errorf("container %s in %T %s must set securityContext.runAsNonRoot to true", container.Name, obj, checker.Key(obj))
+		}
+	})
+}
+
+// ReadOnlyRootFilesystem checks that every container of every workload sets readOnlyRootFilesystem to true
+func ReadOnlyRootFilesystem(exempt ...relatedresource.Key) test.Checks {
+	return perContainer(exempt, func(tc *checker.TestContext, obj metav1.Object, podSpec corev1.PodSpec, container corev1.Container) {
+		if container.SecurityContext == nil || container.SecurityContext.ReadOnlyRootFilesystem == nil || !*container.SecurityContext.ReadOnlyRootFilesystem {
+			tc.T.Errorf("container %s in %T %s must set securityContext.readOnlyRootFilesystem to true", container.Name, obj, checker.Key(obj))
+		}
+	})
+}
+
+// DropAllCapabilities checks that every container of every workload drops ALL capabilities
+func DropAllCapabilities(exempt ...relatedresource.Key) test.Checks {
+	return perContainer(exempt, func(tc *checker.TestContext, obj metav1.Object, podSpec corev1.PodSpec, container corev1.Container) {
+		if container.SecurityContext != nil && container.SecurityContext.Capabilities != nil {
+			for _, capability := range container.SecurityContext.Capabilities.Drop {
+				if capability == "ALL" {
+					return
+				}
+			}
+		}
+		tc.T.Errorf("container %s in %T %s must drop ALL capabilities in securityContext.capabilities.drop", container.Name, obj, checker.Key(obj))
+	})
+}
+
+// NoPrivilegedContainers checks that no container of any workload is privileged
+func NoPrivilegedContainers(exempt ...relatedresource.Key) test.Checks {
+	return perContainer(exempt, func(tc *checker.TestContext, obj metav1.Object, podSpec corev1.PodSpec, container corev1.Container) {
+		if container.SecurityContext != nil && container.SecurityContext.Privileged != nil && *container.SecurityContext.Privileged {
+			tc.T.Errorf("container %s in %T %s must not set securityContext.privileged to true", container.Name, obj, checker.Key(obj))
+		}
+	})
+}
+
+// NoHostPathVolumes checks that no workload mounts a hostPath volume
+func NoHostPathVolumes(exempt ...relatedresource.Key) test.Checks {
+	return perWorkload(exempt, func(tc *checker.TestContext, obj metav1.Object, podSpec corev1.PodSpec) {
+		for _, volume := range podSpec.Volumes {
+			if volume.HostPath != nil {
+				tc.T.Errorf("volume %s in %T %s must not be a hostPath volume: found path %s", volume.Name, obj, checker.Key(obj), volume.HostPath.Path)
+			}
+		}
+	})
+}
+
+// NoHostNetwork checks that no workload uses the network namespace of the host
+func NoHostNetwork(exempt ...relatedresource.Key) test.Checks {
+	return perWorkload(exempt, func(tc *checker.TestContext, obj metav1.Object, podSpec corev1.PodSpec) {
+		if podSpec.HostNetwork {
+			tc.T.Errorf("%T %s must not set hostNetwork to true", obj, checker.Key(obj))
+		}
+	})
+}
+
+// ResourceRequestsAndLimits checks that every container of every workload sets cpu and memory requests and limits
+func ResourceRequestsAndLimits(exempt ...relatedresource.Key) test.Checks {
+	return perContainer(exempt, func(tc *checker.TestContext, obj metav1.Object, podSpec corev1.PodSpec, container corev1.Container) {
+		for _, resourceName := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			if _, ok := container.Resources.Requests[resourceName]; !ok {
+				tc.T.Errorf("container %s in %T %s must set resources.requests.%s", container.Name, obj, checker.Key(obj), resourceName)
+			}
+			if _, ok := container.Resources.Limits[resourceName]; !ok {
+				tc.T.Errorf("container %s in %T %s must set resources.limits.%s", container.Name, obj, checker.Key(obj), resourceName)
+			}
+		}
+	})
+}
+
+// SeccompProfile checks that every container of every workload uses the RuntimeDefault or a Localhost seccomp profile,
+// set on the container or the pod
+func SeccompProfile(exempt ...relatedresource.Key) test.Checks {
+	return perContainer(exempt, func(tc *checker.TestContext, obj metav1.Object, podSpec corev1.PodSpec, container corev1.Container) {
+		var profile *corev1.SeccompProfile
+		if podSpec.SecurityContext != nil {
+			profile = podSpec.SecurityContext.SeccompProfile
+		}
+		if container.SecurityContext != nil && container.SecurityContext.SeccompProfile != nil {
+			profile = container.SecurityContext.SeccompProfile
+		}
+		if profile == nil {
+			tc.T.Errorf("container %s in %T %s must set securityContext.seccompProfile.type to RuntimeDefault or Localhost", container.Name, obj, checker.Key(obj))
+			return
+		}
+		if profile.Type != corev1.SeccompProfileTypeRuntimeDefault && profile.Type != corev1.SeccompProfileTypeLocalhost {
+			tc.T.Errorf("container %s in %T %s must set securityContext.seccompProfile.type to RuntimeDefault or Localhost: found %s", container.Name, obj, checker.Key(obj), profile.Type)
+		}
+	})
+}
+
+// NoAutomountServiceAccountToken checks that every workload sets automountServiceAccountToken to false, either on the
+// pod or on a ServiceAccount deployed by the chart that the pod does not override
+func NoAutomountServiceAccountToken(exempt ...relatedresource.Key) test.Checks {
+	return test.Checks{
+		checker.PerResource(func(tc *checker.TestContext, serviceAccount *corev1.ServiceAccount) {
+			if serviceAccount.AutomountServiceAccountToken != nil {
+				checker.MapSet(tc, "AutomountServiceAccountToken", checker.Key(serviceAccount), *serviceAccount.AutomountServiceAccountToken)
+			}
+		}),
+		checker.PerWorkload(func(tc *checker.TestContext, obj metav1.Object, podTemplateSpec corev1.PodTemplateSpec) {
+			if checker.IsExempt(exempt, checker.Key(obj)) {
+				return
+			}
+			podSpec := podTemplateSpec.Spec
+			if podSpec.AutomountServiceAccountToken != nil {
+				if *podSpec.AutomountServiceAccountToken {
+					tc.T.Errorf("%T %s must not set automountServiceAccountToken to true", obj, checker.Key(obj))
+				}
+				return
+			}
+			serviceAccountName := podSpec.ServiceAccountName
+			if len(serviceAccountName) == 0 {
+				serviceAccountName = "default"
+			}
+			automount, ok := checker.MapGet[string, relatedresource.Key, bool](tc, "AutomountServiceAccountToken", relatedresource.NewKey(obj.GetNamespace(), serviceAccountName))
+			if !ok || automount {
+				tc.T.Errorf("%T %s must set automountServiceAccountToken to false on the pod or on serviceaccount %s", obj, checker.Key(obj), serviceAccountName)
+			}
+		}),
+	}
+}
+
+func perWorkload(exempt []relatedresource.Key, checkFunc func(tc *checker.TestContext, obj metav1.Object, podSpec corev1.PodSpec)) test.Checks {
+	return test.Checks{
+		checker.PerWorkload(func(tc *checker.TestContext, obj metav1.Object, podTemplateSpec corev1.PodTemplateSpec) {
+			if checker.IsExempt(exempt, checker.Key(obj)) {
+				return
+			}
+			checkFunc(tc, obj, podTemplateSpec.Spec)
+		}),
+	}
+}
+
+func perContainer(exempt []relatedresource.Key, checkFunc func(tc *checker.TestContext, obj metav1.Object, podSpec corev1.PodSpec, container corev1.Container)) test.Checks {
+	return perWorkload(exempt, func(tc *checker.TestContext, obj metav1.Object, podSpec corev1.PodSpec) {
+		var containers []corev1.Container
+		containers = append(containers, podSpec.InitContainers...)
+		containers = append(containers, podSpec.Containers...)
+		for _, container := range containers {
+			checkFunc(tc, obj, podSpec, container)
+		}
+	})
+}
@@ -0,0 +1,228 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/rancher/hull/pkg/checker"
+	"github.com/rancher/wrangler/v3/pkg/relatedresource"
+	"github.com/stretchr/testify/assert"
+)
+
+const secureManifest = `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: secure
+  namespace: hull
+automountServiceAccountToken: false
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: secure
+  namespace: hull
+spec:
+  selector:
+    matchLabels:
+      app: secure
+  template:
+    metadata:
+      labels:
+        app: secure
+    spec:
+      serviceAccountName: secure
+      securityContext:
+        runAsNonRoot: true
+        seccompProfile:
+          type: RuntimeDefault
+      containers:
+      - name: secure
+        image: rancher/hull:latest
+        securityContext:
+          readOnlyRootFilesystem: true
+          capabilities:
+            drop:
+            - ALL
+        resources:
+          requests:
+            cpu: 100m
+            memory: 64Mi
+          limits:
+            cpu: 200m
+            memory: 128Mi
+`
+
+const insecureManifest = `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: insecure
+  namespace: hull
+spec:
+  selector:
+    matchLabels:
+      app: insecure
+  template:
+    metadata:
+      labels:
+        app: insecure
+    spec:
+      hostNetwork: true
+      securityContext:
+        runAsNonRoot: true
+      containers:
+      - name: insecure
+        image: rancher/hull:latest
+        securityContext:
+          runAsNonRoot: false
+          privileged: true
+          capabilities:
+            drop:
+            - NET_RAW
+          seccompProfile:
+            type: Unconfined
+        resources:
+          requests:
+            cpu: 100m
+      volumes:
+      - name: host
+        hostPath:
+          path: /var/run
+`
+
+func TestNamedChecks(t *testing.T) {
+	insecureKey := relatedresource.NewKey("hull", "insecure")
+	testCases := []struct {
+		Name          string
+		Manifest      string
+		Exemptions    Exemptions
+		ExpectFailure bool
+	}{
+		{
+			Name:     "Secure Workload",
+			Manifest: secureManifest,
+		},
+		{
+			Name:          "Insecure Workload",
+			Manifest:      insecureManifest,
+			ExpectFailure: true,
+		},
+		{
+			Name:     "Exempt Insecure Workload",
+			Manifest: insecureManifest,
+			Exemptions: Exemptions{
+				RunAsNonRootCheck:                   {insecureKey},
+				ReadOnlyRootFilesystemCheck:         {insecureKey},
+				DropAllCapabilitiesCheck:            {insecureKey},
+				NoPrivilegedContainersCheck:         {insecureKey},
+				NoHostPathVolumesCheck:              {insecureKey},
+				NoHostNetworkCheck:                  {insecureKey},
+				ResourceRequestsAndLimitsCheck:      {insecureKey},
+				SeccompProfileCheck:                 {insecureKey},
+				NoAutomountServiceAccountTokenCheck: {insecureKey},
+			},
+		},
+		{
+			Name:          "Exemption For Other Workload",
+			Manifest:      insecureManifest,
+			Exemptions:    Exemptions{RunAsNonRootCheck: {relatedresource.NewKey("hull", "secure")}},
+			ExpectFailure: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			c, err := checker.NewCheckerFromString(tc.Manifest, "manifest.yaml")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for _, namedCheck := range NamedChecks(tc.Exemptions) {
+				fakeT := &testing.T{}
+				c.Check(fakeT, checker.NewCheckFunc(namedCheck.Checks...))
+				assert.Equal(t, tc.ExpectFailure, fakeT.Failed(), "unexpected result for check %s", namedCheck.Name)
+			}
+		})
+	}
+}
+
+func TestNoAutomountServiceAccountToken(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Manifest      string
+		ExpectFailure bool
+	}{
+		{
+			Name: "Disabled On Pod",
+			Manifest: `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: job
+  namespace: hull
+spec:
+  template:
+    spec:
+      automountServiceAccountToken: false
+      containers:
+      - name: job
+        image: rancher/hull:latest
+`,
+		},
+		{
+			Name: "Enabled On Pod Overrides ServiceAccount",
+			Manifest: `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: default
+  namespace: hull
+automountServiceAccountToken: false
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: job
+  namespace: hull
+spec:
+  template:
+    spec:
+      automountServiceAccountToken: true
+      containers:
+      - name: job
+        image: rancher/hull:latest
+`,
+			ExpectFailure: true,
+		},
+		{
+			Name: "Default ServiceAccount Not In Chart",
+			Manifest: `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: job
+  namespace: hull
+spec:
+  template:
+    spec:
+      containers:
+      - name: job
+        image: rancher/hull:latest
+`,
+			ExpectFailure: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			c, err := checker.NewCheckerFromString(tc.Manifest, "manifest.yaml")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			fakeT := &testing.T{}
+			c.Check(fakeT, checker.NewCheckFunc(NoAutomountServiceAccountToken()...))
+			assert.Equal(t, tc.ExpectFailure, fakeT.Failed())
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package openapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/rancher/hull/pkg/parser"
+	"github.com/rancher/wrangler/v3/pkg/objectset"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const (
+	definitionPrefix = "#/definitions/"
+
+	objectMetaDefinition  = "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"
+	intOrStringDefinition = "io.k8s.apimachinery.pkg.util.intstr.IntOrString"
+	quantityDefinition    = "io.k8s.apimachinery.pkg.api.resource.Quantity"
+)
+
+// bundledSchemas contains the definitions of the Kubernetes OpenAPI v2 spec (with descriptions removed) for
+// each version of Kubernetes in schemas/<version>.json.gz, which are generated by scripts/generate-schemas
+//
+//go:embed schemas/*.json.gz
+var bundledSchemas embed.FS
+
+var bundledSchemasCache sync.Map
+
+// Schemas holds the OpenAPI schemas that rendered objects are validated against, keyed by GroupVersionKind
+type Schemas struct {
+	// KubeVersion is the version of Kubernetes that the built-in schemas were generated from
+	KubeVersion string
+
+	definitions map[string]spec.Schema
+	resources   map[schema.GroupVersionKind]resourceSchema
+	crds        map[schema.GroupKind]string
+}
+
+type resourceSchema struct {
+	schema *spec.Schema
+
+	// source is the CRD that provided the schema or empty for built-in Kubernetes resources
+	source string
+}
+
+type swaggerDefinitions struct {
+	Definitions map[string]spec.Schema `json:"definitions"`
+}
+
+// NewSchemas returns an empty set of schemas that CRDs can be added to
+func NewSchemas() *Schemas {
+	return &Schemas{
+		definitions: make(map[string]spec.Schema),
+		resources:   make(map[schema.GroupVersionKind]resourceSchema),
+		crds:        make(map[schema.GroupKind]string),
+	}
+}
+
+// BundledKubeVersions returns the versions of Kubernetes that have schemas bundled into Hull, in ascending order
+func BundledKubeVersions() []string {
+	entries, err := bundledSchemas.ReadDir("schemas")
+	if err != nil {
+		return nil
+	}
+	var versions []*semver.Version
+	for _, entry := range entries {
+		version, err := semver.NewVersion(strings.TrimSuffix(entry.Name(), ".json.gz"))
+		if err != nil {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	sort.Sort(semver.Collection(versions))
+	kubeVersions := make([]string, len(versions))
+	for i, version := range versions {
+		kubeVersions[i] = version.Original()
+	}
+	return kubeVersions
+}
+
+// LoadKubernetesSchemas returns the bundled schemas for the minor version of Kubernetes closest to kubeVersion
+//
+// If no schemas are bundled for the minor version of kubeVersion, the schemas of the nearest bundled minor version are
+// used (the older one if two are equally near); the KubeVersion of the returned Schemas identifies the version used.
+// Run scripts/generate-schemas to bundle the schemas of every supported version of Kubernetes.
+func LoadKubernetesSchemas(kubeVersion string) (*Schemas, error) {
+	target, err := semver.NewVersion(kubeVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeVersion %s: %s", kubeVersion, err)
+	}
+	var selected string
+	var selectedDistance int64
+	for _, bundledVersion := range BundledKubeVersions() {
+		v := semver.MustParse(bundledVersion)
+		if v.Major() != target.Major() {
+			continue
+		}
+		distance := int64(v.Minor()) - int64(target.Minor())
+		if distance < 0 {
+			distance = -distance
+		}
+		if len(selected) == 0 || distance < selectedDistance {
+			selected = bundledVersion
+			selectedDistance = distance
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no Kubernetes schemas are bundled for kubeVersion %s", kubeVersion)
+	}
+	if cached, ok := bundledSchemasCache.Load(selected); ok {
+		return cached.(*Schemas).copy(), nil
+	}
+	f, err := bundledSchemas.Open(fmt.Sprintf("schemas/%s.json.gz", selected))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	s, err := parseSwagger(gz)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse bundled schemas for Kubernetes %s: %s", selected, err)
+	}
+	s.KubeVersion = selected
+	bundledSchemasCache.Store(selected, s)
+	return s.copy(), nil
+}
+
+// LoadSchemasFromFile returns the schemas defined in an OpenAPI v2 spec, i.e. the output of `kubectl get --raw /openapi/v2`
+func LoadSchemasFromFile(path string) (*Schemas, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s, err := parseSwagger(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse OpenAPI spec %s: %s", path, err)
+	}
+	return s, nil
+}
+
+func parseSwagger(r io.Reader) (*Schemas, error) {
+	var swagger swaggerDefinitions
+	if err := json.NewDecoder(r).Decode(&swagger); err != nil {
+		return nil, err
+	}
+	s := NewSchemas()
+	for name, definition := range swagger.Definitions {
+		s.definitions[name] = definition
+		var gvks []schema.GroupVersionKind
+		if err := definition.Extensions.GetObject("x-kubernetes-group-version-kind", &gvks); err != nil {
+			return nil, fmt.Errorf("invalid x-kubernetes-group-version-kind on %s: %s", name, err)
+		}
+		for _, gvk := range gvks {
+			s.resources[gvk] = resourceSchema{
+				schema: &spec.Schema{SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef(definitionPrefix + name)}},
+			}
+		}
+	}
+	return s, nil
+}
+
+func (s *Schemas) copy() *Schemas {
+	c := NewSchemas()
+	c.KubeVersion = s.KubeVersion
+	for name, definition := range s.definitions {
+		c.definitions[name] = definition
+	}
+	for gvk, resource := range s.resources {
+		c.resources[gvk] = resource
+	}
+	for gk, crd := range s.crds {
+		c.crds[gk] = crd
+	}
+	return c
+}
+
+// Has returns whether a schema exists for the provided GroupVersionKind
+func (s *Schemas) Has(gvk schema.GroupVersionKind) bool {
+	_, ok := s.resources[gvk]
+	return ok
+}
+
+// CRDFor returns the name of the CRD that was added for the provided GroupKind
+func (s *Schemas) CRDFor(gk schema.GroupKind) (string, bool) {
+	crd, ok := s.crds[gk]
+	return crd, ok
+}
+
+// AddCRDs adds the openAPIV3Schema of every served version of the provided CRDs, replacing any existing schemas for the same GroupVersionKind
+func (s *Schemas) AddCRDs(crds ...*apiextensionsv1.CustomResourceDefinition) error {
+	for _, crd := range crds {
+		s.crds[schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}] = crd.Name
+		for _, version := range crd.Spec.Versions {
+			if !version.Served {
+				continue
+			}
+			crdSchema := &spec.Schema{}
+			if version.Schema != nil && version.Schema.OpenAPIV3Schema != nil {
+				data, err := json.Marshal(version.Schema.OpenAPIV3Schema)
+				if err != nil {
+					return err
+				}
+				if err := json.Unmarshal(data, crdSchema); err != nil {
+					return fmt.Errorf("unable to parse schema of version %s of CRD %s: %s", version.Name, crd.Name, err)
+				}
+			} else {
+				// a CRD without a schema preserves all fields
+				crdSchema.AddExtension("x-kubernetes-preserve-unknown-fields", true)
+			}
+			gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.Kind}
+			s.resources[gvk] = resourceSchema{
+				schema: crdSchema,
+				source: crd.Name,
+			}
+		}
+	}
+	return nil
+}
+
+// LoadCRDs returns the CustomResourceDefinitions found in the provided YAML or JSON files
+//
+// If a path is a directory, every .yaml, .yml, or .json file within it is loaded. Objects that are not
+// apiextensions.k8s.io/v1 CustomResourceDefinitions are ignored.
+func LoadCRDs(paths ...string) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	var crds []*apiextensionsv1.CustomResourceDefinition
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			if file != path {
+				switch filepath.Ext(file) {
+				case ".yaml", ".yml", ".json":
+				default:
+					return nil
+				}
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			fileCRDs, err := ParseCRDs(string(data))
+			if err != nil {
+				return fmt.Errorf("unable to parse CRDs in %s: %s", file, err)
+			}
+			crds = append(crds, fileCRDs...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return crds, nil
+}
+
+// ParseCRDs returns the CustomResourceDefinitions found in a Kubernetes manifest
+func ParseCRDs(manifest string) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	objectSet, err := parser.Parse(manifest)
+	if err != nil {
+		return nil, err
+	}
+	return GetCRDs(objectSet)
+}
+
+// GetCRDs returns the CustomResourceDefinitions tracked in an ObjectSet, sorted by name
+func GetCRDs(objectSet *objectset.ObjectSet) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	if objectSet == nil {
+		return nil, nil
+	}
+	var crds []*apiextensionsv1.CustomResourceDefinition
+	for _, obj := range objectSet.ObjectsByGVK()[apiextensionsv1.SchemeGroupVersion.WithKind("CustomResourceDefinition")] {
+		crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+		if !ok {
+			uObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+			if err != nil {
+				return nil, err
+			}
+			crd = &apiextensionsv1.CustomResourceDefinition{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(uObj, crd); err != nil {
+				return nil, fmt.Errorf("unable to convert CRD: %s", err)
+			}
+		}
+		crds = append(crds, crd)
+	}
+	sort.Slice(crds, func(i, j int) bool {
+		return crds[i].Name < crds[j].Name
+	})
+	return crds, nil
+}
@@ -0,0 +1,215 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rancher/hull/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	crdsPath    = utils.MustGetPathFromModuleRoot("testdata", "crds")
+	widgetsPath = utils.MustGetPathFromModuleRoot("testdata", "crds", "widgets.yaml")
+)
+
+func TestBundledKubeVersions(t *testing.T) {
+	assert.Equal(t, []string{"v1.21", "v1.23", "v1.27"}, BundledKubeVersions())
+}
+
+func TestLoadKubernetesSchemas(t *testing.T) {
+	testCases := []struct {
+		Name             string
+		KubeVersion      string
+		ExpectVersion    string
+		ShouldThrowError bool
+	}{
+		{
+			Name:          "Exact Match",
+			KubeVersion:   "v1.23.0",
+			ExpectVersion: "v1.23",
+		},
+		{
+			Name:          "Patch Version",
+			KubeVersion:   "1.27.3",
+			ExpectVersion: "v1.27",
+		},
+		{
+			Name:          "Older Than Bundled",
+			KubeVersion:   "v1.16.0",
+			ExpectVersion: "v1.21",
+		},
+		{
+			Name:          "Closer To Newer Bundled",
+			KubeVersion:   "v1.26.0",
+			ExpectVersion: "v1.27",
+		},
+		{
+			Name:          "Equally Close To Two Bundled",
+			KubeVersion:   "v1.25.0",
+			ExpectVersion: "v1.23",
+		},
+		{
+			Name:          "Newer Than Bundled",
+			KubeVersion:   "v1.32.0",
+			ExpectVersion: "v1.27",
+		},
+		{
+			Name:             "Different Major Version",
+			KubeVersion:      "v2.0.0",
+			ShouldThrowError: true,
+		},
+		{
+			Name:             "Invalid",
+			KubeVersion:      "not-a-version",
+			ShouldThrowError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			s, err := LoadKubernetesSchemas(tc.KubeVersion)
+			if tc.ShouldThrowError {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.ExpectVersion, s.KubeVersion)
+			assert.True(t, s.Has(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}))
+			assert.True(t, s.Has(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}))
+			assert.False(t, s.Has(schema.GroupVersionKind{Group: "hull.cattle.io", Version: "v1", Kind: "Widget"}))
+		})
+	}
+
+	t.Run("Served API", func(t *testing.T) {
+		for _, kubeVersion := range []string{"v1.21.0", "v1.23.0"} {
+			s, err := LoadKubernetesSchemas(kubeVersion)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.True(t, s.Has(schema.GroupVersionKind{Group: "policy", Version: "v1beta1", Kind: "PodSecurityPolicy"}), "expected PodSecurityPolicy schema for %s", kubeVersion)
+		}
+	})
+
+	t.Run("Removed API", func(t *testing.T) {
+		s, err := LoadKubernetesSchemas("v1.27.0")
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.False(t, s.Has(schema.GroupVersionKind{Group: "policy", Version: "v1beta1", Kind: "PodSecurityPolicy"}))
+	})
+
+	t.Run("Copies Are Independent", func(t *testing.T) {
+		s, err := LoadKubernetesSchemas("v1.27.0")
+		if !assert.NoError(t, err) {
+			return
+		}
+		crds, err := LoadCRDs(widgetsPath)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.NoError(t, s.AddCRDs(crds...))
+		other, err := LoadKubernetesSchemas("v1.27.0")
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.False(t, other.Has(schema.GroupVersionKind{Group: "hull.cattle.io", Version: "v1", Kind: "Widget"}))
+	})
+}
+
+func TestLoadSchemasFromFile(t *testing.T) {
+	dir := t.TempDir()
+	validPath := filepath.Join(dir, "swagger.json")
+	swagger := `{
+  "swagger": "2.0",
+  "definitions": {
+    "io.cattle.hull.v1.Hello": {
+      "type": "object",
+      "properties": {"world": {"type": "string"}},
+      "x-kubernetes-group-version-kind": [{"group": "hull.cattle.io", "kind": "Hello", "version": "v1"}]
+    }
+  }
+}`
+	if err := os.WriteFile(validPath, []byte(swagger), 0644); err != nil {
+		t.Error(err)
+		return
+	}
+	invalidPath := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalidPath, []byte("{"), 0644); err != nil {
+		t.Error(err)
+		return
+	}
+
+	s, err := LoadSchemasFromFile(validPath)
+	if assert.NoError(t, err) {
+		assert.True(t, s.Has(schema.GroupVersionKind{Group: "hull.cattle.io", Version: "v1", Kind: "Hello"}))
+		assert.False(t, s.Has(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}))
+	}
+	_, err = LoadSchemasFromFile(invalidPath)
+	assert.Error(t, err)
+	_, err = LoadSchemasFromFile(filepath.Join(dir, "does-not-exist.json"))
+	assert.Error(t, err)
+}
+
+func TestLoadCRDs(t *testing.T) {
+	testCases := []struct {
+		Name             string
+		Paths            []string
+		ExpectCRDs       []string
+		ShouldThrowError bool
+	}{
+		{
+			Name: "No Paths",
+		},
+		{
+			Name:       "File",
+			Paths:      []string{widgetsPath},
+			ExpectCRDs: []string{"widgets.hull.cattle.io"},
+		},
+		{
+			Name:       "Directory",
+			Paths:      []string{crdsPath},
+			ExpectCRDs: []string{"widgets.hull.cattle.io"},
+		},
+		{
+			Name:             "Missing Path",
+			Paths:            []string{filepath.Join(crdsPath, "does-not-exist.yaml")},
+			ShouldThrowError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			crds, err := LoadCRDs(tc.Paths...)
+			if tc.ShouldThrowError {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			var names []string
+			for _, crd := range crds {
+				names = append(names, crd.Name)
+			}
+			assert.Equal(t, tc.ExpectCRDs, names)
+		})
+	}
+}
+
+func TestAddCRDs(t *testing.T) {
+	crds, err := LoadCRDs(widgetsPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+	s := NewSchemas()
+	if !assert.NoError(t, s.AddCRDs(crds...)) {
+		return
+	}
+	assert.True(t, s.Has(schema.GroupVersionKind{Group: "hull.cattle.io", Version: "v1", Kind: "Widget"}))
+	assert.False(t, s.Has(schema.GroupVersionKind{Group: "hull.cattle.io", Version: "v1alpha1", Kind: "Widget"}), "unserved versions should not be added")
+}
@@ -0,0 +1,314 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	openapierrors "k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+// FieldError describes why the value at Path (i.e. .spec.replicas) does not match its schema
+type FieldError struct {
+	Path    string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	path := e.Path
+	if len(path) == 0 {
+		path = "."
+	}
+	return fmt.Sprintf("%s: %s", path, e.Message)
+}
+
+// Validate returns every field of the object that does not match the schema for its GroupVersionKind
+//
+// Unknown fields are reported wherever the schema lists the allowed properties of an object, since they would be
+// dropped by the Kubernetes API server. Validation rules expressed in CEL (x-kubernetes-validations) are not evaluated.
+// Objects whose GroupVersionKind has no schema are not validated; use Has to identify such objects.
+func (s *Schemas) Validate(obj *unstructured.Unstructured) []FieldError {
+	rs, ok := s.resources[obj.GroupVersionKind()]
+	if !ok {
+		return nil
+	}
+	v := &validator{
+		schemas: s,
+		crd:     len(rs.source) > 0,
+	}
+	// null values are dropped by the API server before validation
+	result := v.newValidator("", rs.schema, true).Validate(withoutNulls(obj.Object))
+	var fieldErrs []FieldError
+	for _, err := range result.Errors {
+		fieldErrs = append(fieldErrs, toFieldErrors(err)...)
+	}
+	sort.SliceStable(fieldErrs, func(i, j int) bool {
+		return fieldErrs[i].Path < fieldErrs[j].Path
+	})
+	return fieldErrs
+}
+
+// validator creates validators from the kube-openapi validate package that resolve references to the definitions of
+// the Schemas as they are reached, since the schemas of Kubernetes resources are recursive
+type validator struct {
+	schemas *Schemas
+
+	// crd is set if the object is validated against the schema of a CRD, whose unspecified fields are pruned
+	crd bool
+}
+
+func (v *validator) option(opts *validate.SchemaValidatorOptions) {
+	opts.NewValidatorForField = func(_ string, schema *spec.Schema, _ interface{}, path string, _ strfmt.Registry, _ ...validate.Option) validate.ValueValidator {
+		return v.newValidator(path, schema, false)
+	}
+	opts.NewValidatorForIndex = func(_ int, schema *spec.Schema, _ interface{}, path string, _ strfmt.Registry, _ ...validate.Option) validate.ValueValidator {
+		return v.newValidator(path, schema, false)
+	}
+}
+
+func (v *validator) newValidator(path string, s *spec.Schema, isRoot bool) validate.ValueValidator {
+	s, special, err := v.resolve(s)
+	if err != nil {
+		return &fieldValidator{path: path, check: func(interface{}) string { return err.Error() }}
+	}
+	switch {
+	case special == intOrStringDefinition || isIntOrString(s):
+		return &fieldValidator{path: path, check: checkIntOrString}
+	case special == quantityDefinition:
+		return &fieldValidator{path: path, check: checkQuantity}
+	}
+	return validate.NewSchemaValidator(v.withKnownFields(s, isRoot), nil, path, strfmt.Default, v.option)
+}
+
+// resolve follows any references in the schema and in its allOf, anyOf, oneOf, and not schemas and returns the
+// schema along with the name of the definition if it is one that requires special handling
+func (v *validator) resolve(s *spec.Schema) (*spec.Schema, string, error) {
+	for {
+		ref := s.Ref.String()
+		if len(ref) == 0 {
+			if len(s.AllOf) == 1 && len(s.Properties) == 0 && len(s.Type) == 0 && len(s.AllOf[0].Ref.String()) > 0 {
+				// some schemas wrap a single reference in an allOf
+				s = &s.AllOf[0]
+				continue
+			}
+			break
+		}
+		name := strings.TrimPrefix(ref, definitionPrefix)
+		switch name {
+		case intOrStringDefinition, quantityDefinition:
+			return s, name, nil
+		}
+		definition, ok := v.schemas.definitions[name]
+		if !ok {
+			return nil, "", fmt.Errorf("schema references unknown definition %s", ref)
+		}
+		s = &definition
+	}
+	if len(s.AllOf) == 0 && len(s.AnyOf) == 0 && len(s.OneOf) == 0 && s.Not == nil {
+		return s, "", nil
+	}
+	resolved := *s
+	var err error
+	if resolved.AllOf, err = v.resolveAll(s.AllOf); err != nil {
+		return nil, "", err
+	}
+	if resolved.AnyOf, err = v.resolveAll(s.AnyOf); err != nil {
+		return nil, "", err
+	}
+	if resolved.OneOf, err = v.resolveAll(s.OneOf); err != nil {
+		return nil, "", err
+	}
+	if s.Not != nil {
+		if resolved.Not, _, err = v.resolve(s.Not); err != nil {
+			return nil, "", err
+		}
+	}
+	return &resolved, "", nil
+}
+
+func (v *validator) resolveAll(schemas []spec.Schema) ([]spec.Schema, error) {
+	if len(schemas) == 0 {
+		return nil, nil
+	}
+	resolved := make([]spec.Schema, len(schemas))
+	for i := range schemas {
+		s, _, err := v.resolve(&schemas[i])
+		if err != nil {
+			return nil, err
+		}
+		resolved[i] = *s
+	}
+	return resolved, nil
+}
+
+// withKnownFields returns a copy of the schema that forbids fields that are not listed in its properties, unless it
+// preserves unknown fields
+//
+// The schemas of built-in resources do not list properties for free-form objects, so only the schemas of CRDs forbid
+// every field of objects without properties. Type and object metadata are allowed on custom resources and embedded
+// resources, since their schemas do not describe them.
+func (v *validator) withKnownFields(s *spec.Schema, isRoot bool) *spec.Schema {
+	if s.AdditionalProperties != nil {
+		return s
+	}
+	if preserveUnknownFields, _ := s.Extensions.GetBool("x-kubernetes-preserve-unknown-fields"); preserveUnknownFields {
+		return s
+	}
+	if len(s.Properties) == 0 && (!v.crd || !s.Type.Contains("object")) {
+		return s
+	}
+	known := *s
+	known.AdditionalProperties = &spec.SchemaOrBool{Allows: false}
+	embeddedResource, _ := s.Extensions.GetBool("x-kubernetes-embedded-resource")
+	if (isRoot && v.crd) || embeddedResource {
+		known.Properties = make(map[string]spec.Schema, len(s.Properties)+3)
+		for field, fieldSchema := range s.Properties {
+			known.Properties[field] = fieldSchema
+		}
+		known.Properties["apiVersion"] = spec.Schema{}
+		known.Properties["kind"] = spec.Schema{}
+		known.Properties["metadata"] = spec.Schema{}
+		if isRoot {
+			// the API server validates the metadata of custom resources against the built-in ObjectMeta schema
+			if _, ok := v.schemas.definitions[objectMetaDefinition]; ok {
+				known.Properties["metadata"] = spec.Schema{SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef(definitionPrefix + objectMetaDefinition)}}
+			}
+		}
+	}
+	return &known
+}
+
+// fieldValidator validates a field whose schema is not supported by the kube-openapi validate package
+type fieldValidator struct {
+	path  string
+	check func(value interface{}) string
+}
+
+func (f *fieldValidator) SetPath(path string) {
+	f.path = path
+}
+
+func (f *fieldValidator) Applies(interface{}, reflect.Kind) bool {
+	return true
+}
+
+func (f *fieldValidator) Validate(value interface{}) *validate.Result {
+	result := &validate.Result{}
+	if message := f.check(value); len(message) > 0 {
+		result.AddErrors(FieldError{Path: toFieldPath(f.path), Message: message})
+	}
+	return result
+}
+
+func checkIntOrString(value interface{}) string {
+	switch val := value.(type) {
+	case string, int64, int32, int:
+		return ""
+	case float64:
+		if val == math.Trunc(val) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("must be an integer or a string, found %s", typeOf(value))
+}
+
+func checkQuantity(value interface{}) string {
+	switch val := value.(type) {
+	case string:
+		if _, err := resource.ParseQuantity(val); err != nil {
+			return fmt.Sprintf("invalid quantity %q: %s", val, err)
+		}
+	case int64, int32, int, float64:
+	default:
+		return fmt.Sprintf("must be a quantity, found %s", typeOf(value))
+	}
+	return ""
+}
+
+func isIntOrString(s *spec.Schema) bool {
+	if intOrString, _ := s.Extensions.GetBool("x-kubernetes-int-or-string"); intOrString {
+		return len(s.AnyOf) == 0
+	}
+	return s.Format == "int-or-string"
+}
+
+// toFieldErrors converts an error returned by the kube-openapi validate package into FieldErrors
+func toFieldErrors(err error) []FieldError {
+	var fieldErr FieldError
+	if errors.As(err, &fieldErr) {
+		return []FieldError{fieldErr}
+	}
+	var compositeErr *openapierrors.CompositeError
+	if errors.As(err, &compositeErr) && len(compositeErr.Errors) > 0 {
+		var fieldErrs []FieldError
+		for _, err := range compositeErr.Errors {
+			fieldErrs = append(fieldErrs, toFieldErrors(err)...)
+		}
+		return fieldErrs
+	}
+	var validationErr *openapierrors.Validation
+	if !errors.As(err, &validationErr) {
+		return []FieldError{{Message: err.Error()}}
+	}
+	switch validationErr.Code() {
+	case openapierrors.UnallowedPropertyCode:
+		return []FieldError{{Path: toFieldPath(fmt.Sprintf("%s.%s", validationErr.Name, validationErr.Value)), Message: "unknown field"}}
+	case openapierrors.RequiredFailCode:
+		return []FieldError{{Path: toFieldPath(validationErr.Name), Message: "required field is missing"}}
+	}
+	message := strings.TrimPrefix(validationErr.Error(), fmt.Sprintf("%s in %s ", validationErr.Name, validationErr.In))
+	return []FieldError{{Path: toFieldPath(validationErr.Name), Message: message}}
+}
+
+func toFieldPath(path string) string {
+	if len(path) == 0 {
+		return ""
+	}
+	return "." + strings.TrimPrefix(path, ".")
+}
+
+func withoutNulls(value interface{}) interface{} {
+	switch val := value.(type) {
+	case map[string]interface{}:
+		obj := make(map[string]interface{}, len(val))
+		for k, v := range val {
+			if v != nil {
+				obj[k] = withoutNulls(v)
+			}
+		}
+		return obj
+	case []interface{}:
+		arr := make([]interface{}, len(val))
+		for i, v := range val {
+			arr[i] = withoutNulls(v)
+		}
+		return arr
+	}
+	return value
+}
+
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int64, int32, int:
+		return "integer"
+	case float64:
+		return "number"
+	}
+	return reflect.TypeOf(value).String()
+}
@@ -0,0 +1,264 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+func TestValidate(t *testing.T) {
+	s, err := LoadKubernetesSchemas("v1.27.0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	crds, err := LoadCRDs(widgetsPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.NoError(t, s.AddCRDs(crds...)) {
+		return
+	}
+
+	testCases := []struct {
+		Name         string
+		Object       string
+		ExpectErrors []string
+	}{
+		{
+			Name: "Valid Deployment",
+			Object: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hello
+  labels:
+    app.kubernetes.io/name: hello
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: hello
+  strategy:
+    rollingUpdate:
+      maxUnavailable: 25%
+      maxSurge: 1
+  template:
+    metadata:
+      labels:
+        app: hello
+    spec:
+      containers:
+      - name: hello
+        image: rancher/hull:latest
+        env:
+        - name: HELLO
+          value:
+        resources:
+          limits:
+            cpu: 0.5
+            memory: 128Mi
+          requests:
+            cpu: 1
+`,
+		},
+		{
+			Name: "Unknown Field",
+			Object: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hello
+spec:
+  replicass: 1
+  selector:
+    matchLabels:
+      app: hello
+  template:
+    spec:
+      containers:
+      - name: hello
+        imagee: rancher/hull:latest
+`,
+			ExpectErrors: []string{
+				".spec.replicass: unknown field",
+				".spec.template.spec.containers[0].imagee: unknown field",
+			},
+		},
+		{
+			Name: "Wrong Type",
+			Object: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hello
+  labels:
+    enabled: true
+spec:
+  replicas: "1"
+  selector:
+    matchLabels:
+      app: hello
+  template:
+    spec:
+      containers: hello
+`,
+			ExpectErrors: []string{
+				`.metadata.labels.enabled: must be of type string: "boolean"`,
+				`.spec.replicas: must be of type integer: "string"`,
+				`.spec.template.spec.containers: must be of type array: "string"`,
+			},
+		},
+		{
+			Name: "Missing Required Fields",
+			Object: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hello
+spec:
+  template:
+    spec:
+      containers:
+      - image: rancher/hull:latest
+`,
+			ExpectErrors: []string{
+				".spec.selector: required field is missing",
+				".spec.template.spec.containers[0].name: required field is missing",
+			},
+		},
+		{
+			Name: "Invalid Int Or String",
+			Object: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: hello
+spec:
+  ports:
+  - port: 80
+    targetPort: true
+`,
+			ExpectErrors: []string{
+				".spec.ports[0].targetPort: must be an integer or a string, found boolean",
+			},
+		},
+		{
+			Name: "Invalid Quantity",
+			Object: `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: hello
+spec:
+  containers:
+  - name: hello
+    resources:
+      limits:
+        memory: 128MB
+`,
+			ExpectErrors: []string{
+				`.spec.containers[0].resources.limits.memory: invalid quantity "128MB": quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'`,
+			},
+		},
+		{
+			Name: "Free-Form Object",
+			Object: `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: hello
+  annotations:
+    hello.cattle.io/world: "true"
+data:
+  hello: world
+`,
+		},
+		{
+			Name: "Valid Custom Resource",
+			Object: `
+apiVersion: hull.cattle.io/v1
+kind: Widget
+metadata:
+  name: hello
+  namespace: world
+spec:
+  size: small
+  color: "#00ff00"
+  replicas: 0
+  port: 8080
+  config:
+    anything:
+      goes: here
+`,
+		},
+		{
+			Name: "Invalid Custom Resource",
+			Object: `
+apiVersion: hull.cattle.io/v1
+kind: Widget
+metadata:
+  name: hello
+  namespacee: world
+spec:
+  size: huge
+  color: green
+  replicas: -1
+  port: [8080]
+  extra: true
+`,
+			ExpectErrors: []string{
+				".metadata.namespacee: unknown field",
+				`.spec.color: should match '^#[0-9a-f]{6}$'`,
+				".spec.extra: unknown field",
+				".spec.port: must be an integer or a string, found array",
+				".spec.replicas: should be greater than or equal to 0",
+				".spec.size: should be one of [small medium large]",
+			},
+		},
+		{
+			Name: "Custom Resource Missing Required Fields",
+			Object: `
+apiVersion: hull.cattle.io/v1
+kind: Widget
+metadata:
+  name: hello
+`,
+			ExpectErrors: []string{
+				".spec: required field is missing",
+			},
+		},
+		{
+			Name: "No Schema",
+			Object: `
+apiVersion: hull.cattle.io/v1alpha1
+kind: Widget
+metadata:
+  name: hello
+spec:
+  anything: goes
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal([]byte(tc.Object), &obj.Object); err != nil {
+				t.Error(err)
+				return
+			}
+			var errs []string
+			for _, err := range s.Validate(obj) {
+				errs = append(errs, err.Error())
+			}
+			assert.Equal(t, tc.ExpectErrors, errs)
+		})
+	}
+}
+
+func TestFieldError(t *testing.T) {
+	assert.Equal(t, ".spec: unknown field", FieldError{Path: ".spec", Message: "unknown field"}.Error())
+	assert.Equal(t, ".: must be of type object, found string", FieldError{Message: "must be of type object, found string"}.Error())
+}
@@ -0,0 +1,140 @@
+package test
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/rancher/hull/pkg/checker"
+	"github.com/rancher/hull/pkg/extract"
+	"github.com/rancher/hull/pkg/writer"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Assertion is a built-in check that can be expressed declaratively (i.e. in a suite file) instead of in Go
+//
+// An Assertion selects every rendered object that matches the provided APIVersion, Kind, Name, and Namespace
+// (empty selectors match everything) and asserts on the number of selected objects and/or on the value found
+// at Path in each selected object. Path uses the same syntax as checker.RenderValue (i.e. .spec.containers[0].image).
+type Assertion struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+
+	Count *int `json:"count,omitempty"`
+
+	Path    string      `json:"path,omitempty"`
+	Exists  *bool       `json:"exists,omitempty"`
+	Equals  interface{} `json:"equals,omitempty"`
+	Matches string      `json:"matches,omitempty"`
+}
+
+func (a Assertion) selects(obj *unstructured.Unstructured) bool {
+	if len(a.APIVersion) > 0 && obj.GetAPIVersion() != a.APIVersion {
+		return false
+	}
+	if len(a.Kind) > 0 && obj.GetKind() != a.Kind {
+		return false
+	}
+	if len(a.Name) > 0 && obj.GetName() != a.Name {
+		return false
+	}
+	if len(a.Namespace) > 0 && obj.GetNamespace() != a.Namespace {
+		return false
+	}
+	return true
+}
+
+func (a Assertion) validate() error {
+	if a.Count == nil && len(a.Path) == 0 {
+		return fmt.Errorf("assertion must either provide a count or a path")
+	}
+	if len(a.Path) == 0 && (a.Exists != nil || a.Equals != nil || len(a.Matches) > 0) {
+		return fmt.Errorf("assertion must provide a path to use exists, equals, or matches")
+	}
+	if len(a.Matches) > 0 {
+		if _, err := regexp.Compile(a.Matches); err != nil {
+			return fmt.Errorf("assertion has invalid regex for matches: %s", err)
+		}
+	}
+	return nil
+}
+
+// Check returns a checker.ChainedCheckFunc that runs this Assertion against the rendered objects
+func (a Assertion) Check() checker.ChainedCheckFunc {
+	return checker.OnResources(func(tc *checker.TestContext, objs []*unstructured.Unstructured) {
+		if err := a.validate(); err != nil {
+			tc.T.Error(err)
+			return
+		}
+		var selected []*unstructured.Unstructured
+		for _, obj := range objs {
+			if a.selects(obj) {
+				selected = append(selected, obj)
+			}
+		}
+		if a.Count != nil {
+			assert.Len(tc.T, selected, *a.Count, "expected to find %d object(s) matching %s", *a.Count, a)
+		}
+		if len(a.Path) == 0 {
+			return
+		}
+		for _, obj := range selected {
+			a.checkObject(tc, obj)
+		}
+	})
+}
+
+func (a Assertion) checkObject(tc *checker.TestContext, obj *unstructured.Unstructured) {
+	description := describe(tc, obj, a.Path)
+	val, exists := extract.Field[interface{}](obj.Object, a.Path)
+	if a.Exists != nil {
+		message := fmt.Sprintf("%s: expected existence of %s to be %t", description, a.Path, *a.Exists)
+		if !assert.Equal(tc.T, *a.Exists, exists, message) {
+			reportFailure(tc, obj, a.Path, message)
+		}
+	}
+	if a.Equals == nil && len(a.Matches) == 0 {
+		return
+	}
+	if !exists {
+		message := fmt.Sprintf("%s: could not find field %s", description, a.Path)
+		tc.T.Error(message)
+		reportFailure(tc, obj, a.Path, message)
+		return
+	}
+	message := fmt.Sprintf("%s: unexpected value for %s", description, a.Path)
+	if a.Equals != nil && !assert.JSONEq(tc.T, checker.ToJSON(a.Equals), checker.ToJSON(val), message) {
+		reportFailure(tc, obj, a.Path, fmt.Sprintf("%s: expected %s, found %s", message, checker.ToJSON(a.Equals), checker.ToJSON(val)))
+	}
+	if len(a.Matches) > 0 && !assert.Regexp(tc.T, regexp.MustCompile(a.Matches), fmt.Sprint(val), message) {
+		reportFailure(tc, obj, a.Path, fmt.Sprintf("%s: expected match for %s, found %v", message, a.Matches, val))
+	}
+}
+
+// describe returns the kind and key of the object along with the location in the chart's templates that rendered the
+// field at the path, if known
+func describe(tc *checker.TestContext, obj *unstructured.Unstructured, path string) string {
+	description := fmt.Sprintf("%s %s", obj.GetKind(), checker.Key(obj))
+	if source := tc.SourceOf(obj); len(source.Template) > 0 {
+		description += fmt.Sprintf(" (%s)", source.Field(path))
+	}
+	return description
+}
+
+// reportFailure writes the failure to the markdown report at the location in the chart's templates that rendered the
+// field at the path, if known
+func reportFailure(tc *checker.TestContext, obj *unstructured.Unstructured, path, message string) {
+	source := tc.SourceOf(obj)
+	if len(source.Template) == 0 {
+		return
+	}
+	if _, err := writer.NewFailureWriter(tc.T, source.Field(path)).Write([]byte(message)); err != nil {
+		tc.T.Error(err)
+	}
+}
+
+func (a Assertion) String() string {
+	return fmt.Sprintf("{apiVersion: %q, kind: %q, name: %q, namespace: %q}", a.APIVersion, a.Kind, a.Name, a.Namespace)
+}
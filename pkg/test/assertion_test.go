@@ -0,0 +1,176 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/rancher/hull/pkg/checker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssertion(t *testing.T) {
+	manifest := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hello
+  namespace: world
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - name: hello
+        image: rancher/hello:v0.0.0
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: hello
+  namespace: world
+data:
+  config: rancher
+`
+	one := 1
+	two := 2
+	yes := true
+	no := false
+
+	testCases := []struct {
+		Name       string
+		Assertion  Assertion
+		ShouldFail bool
+	}{
+		{
+			Name:       "Empty",
+			Assertion:  Assertion{},
+			ShouldFail: true,
+		},
+		{
+			Name: "Count All",
+			Assertion: Assertion{
+				Count: &two,
+			},
+		},
+		{
+			Name: "Count Kind",
+			Assertion: Assertion{
+				Kind:  "ConfigMap",
+				Count: &one,
+			},
+		},
+		{
+			Name: "Wrong Count",
+			Assertion: Assertion{
+				APIVersion: "apps/v1",
+				Count:      &two,
+			},
+			ShouldFail: true,
+		},
+		{
+			Name: "Equals Number",
+			Assertion: Assertion{
+				Kind:   "Deployment",
+				Path:   ".spec.replicas",
+				Equals: 2,
+			},
+		},
+		{
+			Name: "Equals Wrong Number",
+			Assertion: Assertion{
+				Kind:   "Deployment",
+				Path:   ".spec.replicas",
+				Equals: 3,
+			},
+			ShouldFail: true,
+		},
+		{
+			Name: "Equals Map",
+			Assertion: Assertion{
+				Kind:   "ConfigMap",
+				Name:   "hello",
+				Path:   ".data",
+				Equals: map[string]interface{}{"config": "rancher"},
+			},
+		},
+		{
+			Name: "Equals Missing Field",
+			Assertion: Assertion{
+				Kind:   "ConfigMap",
+				Path:   ".data.missing",
+				Equals: "rancher",
+			},
+			ShouldFail: true,
+		},
+		{
+			Name: "Exists",
+			Assertion: Assertion{
+				Namespace: "world",
+				Path:      ".metadata.name",
+				Exists:    &yes,
+			},
+		},
+		{
+			Name: "Does Not Exist",
+			Assertion: Assertion{
+				Kind:   "Deployment",
+				Path:   ".spec.template.spec.containers[0].args",
+				Exists: &no,
+			},
+		},
+		{
+			Name: "Unexpectedly Exists",
+			Assertion: Assertion{
+				Kind:   "Deployment",
+				Path:   ".spec.template.spec.containers[0].image",
+				Exists: &no,
+			},
+			ShouldFail: true,
+		},
+		{
+			Name: "Matches",
+			Assertion: Assertion{
+				Kind:    "Deployment",
+				Path:    ".spec.template.spec.containers[0].image",
+				Matches: "^rancher/.*:v[0-9.]+$",
+			},
+		},
+		{
+			Name: "Does Not Match",
+			Assertion: Assertion{
+				Kind:    "Deployment",
+				Path:    ".spec.template.spec.containers[0].image",
+				Matches: ":latest$",
+			},
+			ShouldFail: true,
+		},
+		{
+			Name: "Invalid Regex",
+			Assertion: Assertion{
+				Path:    ".metadata.name",
+				Matches: "(",
+			},
+			ShouldFail: true,
+		},
+		{
+			Name: "Selects Nothing",
+			Assertion: Assertion{
+				Name:   "does-not-exist",
+				Path:   ".metadata.name",
+				Equals: "does-not-exist",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			c, err := checker.NewCheckerFromString(manifest, "manifest.yaml")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			fakeT := &testing.T{}
+			c.Check(fakeT, checker.NewCheckFunc(tc.Assertion.Check()))
+			assert.Equal(t, tc.ShouldFail, fakeT.Failed())
+		})
+	}
+}
@@ -190,6 +190,26 @@ func (t *Tracker) CalculateCoverage() (float64, string) {
 			strings.Join(usedReferences, "\n- ")
 }
 
+// UncoveredFields returns the fields referenced by the chart whose references are not all covered, in sorted order
+func (t *Tracker) UncoveredFields() []string {
+	if t == nil {
+		return nil
+	}
+	fieldSet := map[string]bool{}
+	for key, templateTracker := range t.FieldUsage {
+		if templateTracker.IsCovered() {
+			continue
+		}
+		fieldSet[strings.Split(key, " : ")[0]] = true
+	}
+	fields := make([]string, 0, len(fieldSet))
+	for field := range fieldSet {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
 type FieldTracker map[string]*TemplateTracker
 
 func NewFieldTracker() FieldTracker {
@@ -361,3 +361,36 @@ func TestTracker(t *testing.T) {
 		})
 	}
 }
+
+func TestUncoveredFields(t *testing.T) {
+	usage := &tpl.TemplateUsage{
+		Files: map[string]*parse.Result{
+			"configmap.yaml": {
+				Fields: []string{".Values.hello", ".Values.rancher"},
+			},
+			"deployment.yaml": {
+				Fields:        []string{".Values.world"},
+				TemplateCalls: []string{"example-chart.name"},
+			},
+		},
+		NamedTemplates: map[string]*parse.Result{
+			"example-chart.name": {
+				Fields: []string{".Values.hello"},
+			},
+		},
+	}
+	tracker := NewTracker(usage, false)
+	assert.Equal(t, []string{".Values.hello", ".Values.rancher", ".Values.world"}, tracker.UncoveredFields())
+
+	err := tracker.Record(chart.NewTemplateOptions("hello", "world").SetValue("world", "hello"), []string{".Values.world"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{".Values.hello", ".Values.rancher"}, tracker.UncoveredFields())
+
+	// covering the field also covers its references within named templates
+	err = tracker.Record(chart.NewTemplateOptions("hello", "world").SetValue("hello", "world"), []string{".Values.hello"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{".Values.rancher"}, tracker.UncoveredFields())
+
+	var nilTracker *Tracker
+	assert.Nil(t, nilTracker.UncoveredFields())
+}
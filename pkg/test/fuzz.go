@@ -0,0 +1,274 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/rancher/hull/pkg/chart"
+	"github.com/rancher/hull/pkg/checker"
+	helmChartUtil "helm.sh/helm/v3/pkg/chartutil"
+)
+
+const (
+	defaultMaxMutations = 8
+
+	// bytesPerMutation is the number of bytes of fuzz input consumed by each mutation: the field to set, the kind
+	// of value to set it to, and a byte used to derive the value
+	bytesPerMutation = 3
+)
+
+var fuzzStrings = []string{"", " ", generatedString, "0", "-1", "true", "null", "a:b", "a,b", "{}", "[]", "\n", "ü", strings.Repeat("a", 256)}
+
+// FuzzOptions configures Fuzz
+type FuzzOptions struct {
+	// MaxMutations is the maximum number of fields that are set on each render (default 8)
+	MaxMutations int
+
+	// OmitNamedChecks are the names of NamedChecks of the Suite that should not be run against fuzzed values
+	OmitNamedChecks []string
+}
+
+type fuzzField struct {
+	key    string
+	values []interface{}
+}
+
+// Fuzz renders the chart of the Suite with values mutated by Go's native fuzzing engine
+//
+// Each input selects a base Case (or the chart's defaults) and a series of fields from values.yaml and values.schema.json
+// to set to values derived from the schema, the type of the field's default, or null. Inputs that are rejected by
+// values.schema.json or that fail to render due to `required` or `fail` are skipped; any other template error, any
+// error parsing the rendered manifests, or any failing NamedCheck fails the test with the `helm template` command that
+// reproduces it. Call Fuzz from a FuzzXxx function and run it with `go test -fuzz`.
+func Fuzz(f *testing.F, s *Suite, opts *FuzzOptions) {
+	s = s.setDefaults()
+	if opts == nil {
+		opts = &FuzzOptions{}
+	}
+	maxMutations := opts.MaxMutations
+	if maxMutations <= 0 {
+		maxMutations = defaultMaxMutations
+	}
+	c, err := chart.NewChart(s.ChartPath)
+	if err != nil {
+		f.Fatal(err)
+	}
+	fields, err := collectFuzzFields(c)
+	if err != nil {
+		f.Fatal(err)
+	}
+	chartName := c.GetHelmChart().Name()
+	baseOptions := []*chart.TemplateOptions{chart.NewTemplateOptions(chartName, "default")}
+	if s.DefaultValues != nil {
+		baseOptions[0].Values = s.DefaultValues.MergeValues()
+	}
+	for _, tc := range s.Cases {
+		baseOptions = append(baseOptions, tc.TemplateOptions)
+	}
+	var namedChecks []NamedCheck
+	for _, check := range s.NamedChecks {
+		var skip bool
+		for _, omitCheck := range opts.OmitNamedChecks {
+			if check.Name == omitCheck {
+				skip = true
+			}
+		}
+		if !skip {
+			namedChecks = append(namedChecks, check)
+		}
+	}
+
+	// seed the corpus with each base case and with every field set to each kind of value
+	for i := range baseOptions {
+		f.Add([]byte{byte(i)})
+	}
+	for i, field := range fields {
+		for j := range field.values {
+			f.Add([]byte{0, byte(i), byte(j), 0})
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) == 0 {
+			return
+		}
+		templateOptions := toFuzzTemplateOptions(baseOptions[int(data[0])%len(baseOptions)], fields, data[1:], maxMutations)
+		template, renderValues, err := renderFuzzInput(c, templateOptions)
+		if err != nil {
+			if _, ok := err.(errFuzzInputRejected); ok {
+				t.Skip(err)
+			}
+			t.Fatalf("%s\nreproduce with: %s", err, templateOptions)
+		}
+		beforeChecks := Checks{
+			checker.Once(func(tctx *checker.TestContext) {
+				tctx.RenderValues = renderValues
+			}),
+		}
+		if s.PreCheck != nil {
+			beforeChecks = append(beforeChecks, checker.Once(s.PreCheck))
+		}
+		for _, check := range namedChecks {
+			template.Check(t, checker.NewCheckFunc(
+				append(beforeChecks, check.Checks...)...,
+			))
+			if t.Failed() {
+				t.Fatalf("named check %s failed\nreproduce with: %s", check.Name, templateOptions)
+			}
+		}
+	})
+}
+
+// errFuzzInputRejected is returned for inputs that the chart rejects on purpose
+type errFuzzInputRejected struct {
+	err error
+}
+
+func (e errFuzzInputRejected) Error() string {
+	return e.err.Error()
+}
+
+// renderFuzzInput renders the chart, returning an errFuzzInputRejected if the values are rejected by values.schema.json,
+// `required`, or `fail` and any other error if the chart is unable to render
+func renderFuzzInput(c chart.Chart, templateOptions *chart.TemplateOptions) (chart.Template, helmChartUtil.Values, error) {
+	renderValues, err := c.RenderValues(templateOptions)
+	if err != nil {
+		return nil, nil, errFuzzInputRejected{err: fmt.Errorf("values are not valid: %s", err)}
+	}
+	template, err := c.RenderTemplate(templateOptions)
+	if err != nil {
+		if executionErrorRe.MatchString(err.Error()) {
+			return nil, nil, errFuzzInputRejected{err: fmt.Errorf("chart failed to render with an expected error: %s", err)}
+		}
+		return nil, nil, fmt.Errorf("failed to render template: %s", err)
+	}
+	return template, renderValues, nil
+}
+
+func toFuzzTemplateOptions(base *chart.TemplateOptions, fields []fuzzField, data []byte, maxMutations int) *chart.TemplateOptions {
+	templateOptions := *base
+	values := chart.NewValues()
+	for i := 0; len(fields) > 0 && i+bytesPerMutation <= len(data) && i/bytesPerMutation < maxMutations; i += bytesPerMutation {
+		field := fields[int(data[i])%len(fields)]
+		value := field.values[int(data[i+1])%len(field.values)]
+		values = values.Set(field.key, deriveFuzzValue(value, data[i+2]))
+	}
+	templateOptions.Values = base.Values.MergeValues(values)
+	return &templateOptions
+}
+
+// deriveFuzzValue returns a value of the same type as value that is derived from b
+func deriveFuzzValue(value interface{}, b byte) interface{} {
+	if b == 0 {
+		return value
+	}
+	switch val := value.(type) {
+	case string:
+		return fuzzStrings[int(b)%len(fuzzStrings)]
+	case int64:
+		return int64(b) - 128
+	case float64:
+		return float64(int(b)-128) / 4
+	case []interface{}:
+		arr := make([]interface{}, int(b)%4)
+		for i := range arr {
+			if len(val) > 0 {
+				arr[i] = val[0]
+			} else {
+				arr[i] = generatedString
+			}
+		}
+		return arr
+	}
+	return value
+}
+
+// collectFuzzFields returns every field of the chart's values.yaml and values.schema.json along with the kinds of values it can be set to
+func collectFuzzFields(c chart.Chart) ([]fuzzField, error) {
+	helmChart := c.GetHelmChart()
+	var valuesSchema map[string]interface{}
+	if len(helmChart.Schema) > 0 {
+		if err := json.Unmarshal(helmChart.Schema, &valuesSchema); err != nil {
+			return nil, fmt.Errorf("unable to parse values.schema.json of chart %s: %s", helmChart.Name(), err)
+		}
+	}
+	g := &valueGenerator{root: valuesSchema}
+	keys := map[string]bool{}
+	collectValuesKeys(helmChart.Values, "", keys)
+	g.collectSchemaKeys(valuesSchema, "", keys, 0)
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+	fields := make([]fuzzField, len(sortedKeys))
+	for i, key := range sortedKeys {
+		path := strings.Split(key, ".")
+		defaultValue := lookupDefault(helmChart.Values, path)
+		fieldSchema := g.lookup(valuesSchema, path)
+		values := []interface{}{nil}
+		if generated, err := g.generate(fieldSchema, defaultValue, 0); err == nil {
+			values = append(values, generated)
+		}
+		if fieldSchema != nil || defaultValue == nil {
+			// the type of the field is not fixed by its default, so try values of every type and rely on
+			// values.schema.json to reject values of the wrong type
+			values = append(values, generatedString, int64(1), true, []interface{}{generatedString}, map[string]interface{}{"hull": generatedString})
+		} else {
+			values = append(values, zeroValue(defaultValue))
+		}
+		fields[i] = fuzzField{
+			key:    key,
+			values: values,
+		}
+	}
+	return fields, nil
+}
+
+func collectValuesKeys(values map[string]interface{}, prefix string, keys map[string]bool) {
+	for key, value := range values {
+		if strings.Contains(key, ".") {
+			// keys containing dots cannot be set via --set-json
+			continue
+		}
+		keys[prefix+key] = true
+		if m, ok := value.(map[string]interface{}); ok {
+			collectValuesKeys(m, prefix+key+".", keys)
+		}
+	}
+}
+
+func (g *valueGenerator) collectSchemaKeys(s map[string]interface{}, prefix string, keys map[string]bool, depth int) {
+	s = g.resolve(s, depth)
+	if s == nil || depth > maxSchemaDepth {
+		return
+	}
+	properties, _ := s["properties"].(map[string]interface{})
+	for key, property := range properties {
+		if strings.Contains(key, ".") {
+			continue
+		}
+		keys[prefix+key] = true
+		propertySchema, _ := property.(map[string]interface{})
+		g.collectSchemaKeys(propertySchema, prefix+key+".", keys, depth+1)
+	}
+}
+
+func zeroValue(value interface{}) interface{} {
+	switch value.(type) {
+	case bool:
+		return false
+	case float64, int64, int:
+		return int64(0)
+	case string:
+		return ""
+	case []interface{}:
+		return []interface{}{}
+	case map[string]interface{}:
+		return map[string]interface{}{}
+	}
+	return nil
+}
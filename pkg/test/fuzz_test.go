@@ -0,0 +1,167 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/rancher/hull/pkg/chart"
+	"github.com/rancher/hull/pkg/checker"
+	"github.com/rancher/hull/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	fuzzChartPath = utils.MustGetPathFromModuleRoot("testdata", "charts", "fuzz-chart")
+)
+
+func FuzzSimpleChart(f *testing.F) {
+	Fuzz(f, &Suite{
+		ChartPath: simpleChartPath,
+		Cases: []Case{
+			{
+				Name:            "Set .Values.data",
+				TemplateOptions: chart.NewTemplateOptions("simple-chart", "default").Set("data", map[string]string{"hello": "world"}),
+			},
+		},
+		NamedChecks: []NamedCheck{
+			{
+				Name: "ConfigMaps Are Rendered",
+				Checks: Checks{
+					checker.PerResource(func(tc *checker.TestContext, configMap *corev1.ConfigMap) {
+						assert.Contains(tc.T, configMap.Data, "config")
+					}),
+				},
+			},
+		},
+	}, nil)
+}
+
+func TestRenderFuzzInput(t *testing.T) {
+	testCases := []struct {
+		Name            string
+		ChartPath       string
+		TemplateOptions *chart.TemplateOptions
+		ExpectRejected  bool
+		ExpectError     string
+	}{
+		{
+			Name:            "Defaults",
+			ChartPath:       fuzzChartPath,
+			TemplateOptions: chart.NewTemplateOptions("fuzz-chart", "default"),
+		},
+		{
+			Name:            "Required Value Is Null",
+			ChartPath:       fuzzChartPath,
+			TemplateOptions: chart.NewTemplateOptions("fuzz-chart", "default").Set("name", nil),
+			ExpectRejected:  true,
+		},
+		{
+			Name:            "Map Is Null",
+			ChartPath:       fuzzChartPath,
+			TemplateOptions: chart.NewTemplateOptions("fuzz-chart", "default").Set("image", nil),
+			ExpectError:     "nil pointer evaluating interface {}.repository",
+		},
+		{
+			Name:            "Rejected By Schema",
+			ChartPath:       generateChartPath,
+			TemplateOptions: chart.NewTemplateOptions("generate-chart", "default").Set("replicas", 0),
+			ExpectRejected:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			c, err := chart.NewChart(tc.ChartPath)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			template, renderValues, err := renderFuzzInput(c, tc.TemplateOptions)
+			if tc.ExpectRejected {
+				assert.IsType(t, errFuzzInputRejected{}, err)
+				return
+			}
+			if len(tc.ExpectError) > 0 {
+				if assert.NotNil(t, err) {
+					assert.NotEqual(t, errFuzzInputRejected{}, err)
+					assert.Contains(t, err.Error(), tc.ExpectError)
+				}
+				return
+			}
+			assert.Nil(t, err)
+			assert.NotNil(t, template)
+			assert.NotNil(t, renderValues)
+		})
+	}
+}
+
+func TestToFuzzTemplateOptions(t *testing.T) {
+	c, err := chart.NewChart(fuzzChartPath)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	fields, err := collectFuzzFields(c)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	var keys []string
+	for _, field := range fields {
+		keys = append(keys, field.key)
+	}
+	assert.Equal(t, []string{"image", "image.repository", "image.tag", "name", "replicas"}, keys)
+
+	base := chart.NewTemplateOptions("fuzz-chart", "default").Set("name", "base")
+	testCases := []struct {
+		Name         string
+		Data         []byte
+		MaxMutations int
+		ExpectValues []string
+	}{
+		{
+			Name:         "No Mutations",
+			Data:         []byte{},
+			MaxMutations: 8,
+			ExpectValues: []string{"name=\"base\""},
+		},
+		{
+			Name:         "Set Null",
+			Data:         []byte{0, 0, 0},
+			MaxMutations: 8,
+			ExpectValues: []string{"name=\"base\"", "image=null"},
+		},
+		{
+			Name:         "Set Zero Value",
+			Data:         []byte{4, 2, 0},
+			MaxMutations: 8,
+			ExpectValues: []string{"name=\"base\"", "replicas=0"},
+		},
+		{
+			Name:         "Set Derived Value",
+			Data:         []byte{3, 1, 1},
+			MaxMutations: 8,
+			ExpectValues: []string{"name=\"base\"", "name=\" \""},
+		},
+		{
+			Name:         "Incomplete Mutation",
+			Data:         []byte{3, 1},
+			MaxMutations: 8,
+			ExpectValues: []string{"name=\"base\""},
+		},
+		{
+			Name:         "Max Mutations",
+			Data:         []byte{0, 0, 0, 4, 2, 0},
+			MaxMutations: 1,
+			ExpectValues: []string{"name=\"base\"", "image=null"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			templateOptions := toFuzzTemplateOptions(base, fields, tc.Data, tc.MaxMutations)
+			assert.Equal(t, tc.ExpectValues, templateOptions.Values.JSONValues)
+			assert.Equal(t, []string{"name=\"base\""}, base.Values.JSONValues, "base options should not be modified")
+		})
+	}
+}
@@ -0,0 +1,509 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rancher/hull/pkg/chart"
+	"github.com/rancher/hull/pkg/test/coverage"
+	"github.com/rancher/hull/pkg/tpl"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	generatedString = "hull-test"
+
+	// maxSchemaDepth limits how many references are followed when generating a value from a schema
+	maxSchemaDepth = 10
+)
+
+var generatedStringCandidates = []string{generatedString, "hull", "test", "a", "1"}
+
+var generatedFormatCandidates = map[string]string{
+	"uri":       "https://hull.cattle.io",
+	"url":       "https://hull.cattle.io",
+	"email":     "hull@cattle.io",
+	"hostname":  "hull.cattle.io",
+	"ipv4":      "10.0.0.1",
+	"ipv6":      "::1",
+	"date":      "2024-01-01",
+	"date-time": "2024-01-01T00:00:00Z",
+	"uuid":      "00000000-0000-0000-0000-000000000000",
+}
+
+// GeneratedCase is a Case proposed by GenerateCases that sets Field to Value
+type GeneratedCase struct {
+	Case
+
+	// Field is the .Values field set by the Case (i.e. .Values.replicas)
+	Field string
+	// Value is the value that the Case sets the field to
+	Value interface{}
+	// Warning explains why the Case may need to be modified by hand, i.e. if it fails to render
+	Warning string
+}
+
+// UncoveredFields returns the .Values fields referenced by the chart that are not yet covered by the NamedChecks run on each Case
+func (s *Suite) UncoveredFields(includeSubcharts bool) ([]string, error) {
+	s = s.setDefaults()
+	c, err := chart.NewChart(s.ChartPath)
+	if err != nil {
+		return nil, err
+	}
+	templateUsage, err := tpl.CollectTemplateUsage(c)
+	if err != nil {
+		return nil, err
+	}
+	coverageTracker := coverage.NewTracker(templateUsage, includeSubcharts)
+	for _, tc := range s.Cases {
+		for _, check := range s.namedChecksFor(tc) {
+			if err := coverageTracker.Record(tc.TemplateOptions, check.Covers); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, tc := range s.FailureCases {
+		if err := coverageTracker.Record(tc.TemplateOptions, tc.Covers); err != nil {
+			return nil, err
+		}
+	}
+	var fields []string
+	for _, field := range coverageTracker.UncoveredFields() {
+		if strings.HasPrefix(field, ".Values.") {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// GenerateCases proposes a Case for each of the provided .Values fields that sets the field to a new value
+//
+// Values are generated from the chart's values.schema.json if it describes the field, so that they satisfy the
+// type, enum, pattern, and range constraints of the schema; otherwise, a value of the same type as the field's
+// default in values.yaml is used. Each proposed Case is rendered and a Warning is set on any Case that fails to render.
+func GenerateCases(c chart.Chart, fields []string) ([]GeneratedCase, error) {
+	helmChart := c.GetHelmChart()
+	var valuesSchema map[string]interface{}
+	if len(helmChart.Schema) > 0 {
+		if err := json.Unmarshal(helmChart.Schema, &valuesSchema); err != nil {
+			return nil, fmt.Errorf("unable to parse values.schema.json of chart %s: %s", helmChart.Name(), err)
+		}
+	}
+	var cases []GeneratedCase
+	for _, field := range fields {
+		key := strings.TrimPrefix(field, ".Values.")
+		if key == field || len(key) == 0 {
+			return nil, fmt.Errorf("cannot generate case for %s: only .Values fields are supported", field)
+		}
+		path := strings.Split(key, ".")
+		defaultValue := lookupDefault(helmChart.Values, path)
+		g := &valueGenerator{root: valuesSchema}
+		value, err := g.generate(g.lookup(valuesSchema, path), defaultValue, 0)
+		generatedCase := GeneratedCase{
+			Case: Case{
+				Name:            fmt.Sprintf("Set %s to %s", field, toCaseNameValue(value)),
+				TemplateOptions: chart.NewTemplateOptions(helmChart.Name(), "default").Set(key, value),
+			},
+			Field: field,
+			Value: value,
+		}
+		if err != nil {
+			generatedCase.Warning = err.Error()
+		} else if _, err := c.RenderTemplate(generatedCase.TemplateOptions); err != nil {
+			generatedCase.Warning = fmt.Sprintf("failed to render: %s", err)
+		}
+		cases = append(cases, generatedCase)
+	}
+	return cases, nil
+}
+
+// FormatCasesAsGo returns Go source declaring the cases as a []test.Case
+func FormatCasesAsGo(cases []GeneratedCase) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("package generated\n\n")
+	buf.WriteString("import (\n\"github.com/rancher/hull/pkg/chart\"\n\"github.com/rancher/hull/pkg/test\"\n)\n\n")
+	buf.WriteString("// generatedCases set fields that are not yet covered; add each field to the Covers of a NamedCheck that verifies it\n")
+	buf.WriteString("var generatedCases = []test.Case{\n")
+	for _, c := range cases {
+		if len(c.Warning) > 0 {
+			fmt.Fprintf(&buf, "// TODO: %s\n", strings.ReplaceAll(c.Warning, "\n", " "))
+		}
+		fmt.Fprintf(&buf, "{\nName: %q,\n\nTemplateOptions: chart.NewTemplateOptions(%q, %q).Set(%q, %s),\n},\n",
+			c.Name, c.TemplateOptions.Release.Name, c.TemplateOptions.Release.Namespace, strings.TrimPrefix(c.Field, ".Values."), toGoLiteral(c.Value),
+		)
+	}
+	buf.WriteString("}\n")
+	return format.Source(buf.Bytes())
+}
+
+// FormatCasesAsSuite returns a SuiteFile containing the cases in YAML
+func FormatCasesAsSuite(cases []GeneratedCase) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("# The following cases set fields that are not yet covered; add each field to the covers of a named check that verifies it\n")
+	suiteFile := SuiteFile{}
+	for _, c := range cases {
+		if len(c.Warning) > 0 {
+			fmt.Fprintf(&buf, "# TODO (%s): %s\n", c.Name, strings.ReplaceAll(c.Warning, "\n", " "))
+		}
+		suiteFile.Cases = append(suiteFile.Cases, CaseFile{
+			Name: c.Name,
+			TemplateOptionsFile: TemplateOptionsFile{
+				ReleaseName: c.TemplateOptions.Release.Name,
+				Namespace:   c.TemplateOptions.Release.Namespace,
+				ValuesFile: ValuesFile{
+					SetJSON: map[string]interface{}{
+						strings.TrimPrefix(c.Field, ".Values."): c.Value,
+					},
+				},
+			},
+		})
+	}
+	data, err := yaml.Marshal(suiteFile)
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(data)
+	return buf.Bytes(), nil
+}
+
+func lookupDefault(values map[string]interface{}, path []string) interface{} {
+	var current interface{} = values
+	for _, key := range path {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[key]
+	}
+	return current
+}
+
+type valueGenerator struct {
+	root map[string]interface{}
+}
+
+// lookup returns the schema that describes the value at path or nil if no such schema exists
+func (g *valueGenerator) lookup(s map[string]interface{}, path []string) map[string]interface{} {
+	for _, key := range path {
+		s = g.resolve(s, 0)
+		if s == nil {
+			return nil
+		}
+		if properties, ok := s["properties"].(map[string]interface{}); ok {
+			if property, ok := properties[key].(map[string]interface{}); ok {
+				s = property
+				continue
+			}
+		}
+		additionalProperties, ok := s["additionalProperties"].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		s = additionalProperties
+	}
+	return g.resolve(s, 0)
+}
+
+// resolve follows local references (i.e. #/definitions/name or #/$defs/name) in the schema
+func (g *valueGenerator) resolve(s map[string]interface{}, depth int) map[string]interface{} {
+	for s != nil && depth < maxSchemaDepth {
+		ref, ok := s["$ref"].(string)
+		if !ok {
+			return s
+		}
+		depth++
+		var target interface{} = g.root
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			m, ok := target.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			target = m[part]
+		}
+		s, _ = target.(map[string]interface{})
+	}
+	return s
+}
+
+func (g *valueGenerator) generate(s map[string]interface{}, defaultValue interface{}, depth int) (interface{}, error) {
+	s = g.resolve(s, depth)
+	if s == nil || depth > maxSchemaDepth {
+		return inferValue(defaultValue), nil
+	}
+	if constValue, ok := s["const"]; ok {
+		return constValue, nil
+	}
+	if enum, ok := s["enum"].([]interface{}); ok && len(enum) > 0 {
+		return pickDifferent(enum, defaultValue), nil
+	}
+	if examples, ok := s["examples"].([]interface{}); ok && len(examples) > 0 {
+		return pickDifferent(examples, defaultValue), nil
+	}
+	switch schemaType(s) {
+	case "boolean":
+		if b, ok := defaultValue.(bool); ok {
+			return !b, nil
+		}
+		return true, nil
+	case "integer", "number":
+		return generateNumber(s, defaultValue)
+	case "string":
+		return generateString(s, defaultValue)
+	case "array":
+		items, _ := s["items"].(map[string]interface{})
+		var defaultItem interface{}
+		if defaultArray, ok := defaultValue.([]interface{}); ok && len(defaultArray) > 0 {
+			defaultItem = defaultArray[0]
+		}
+		item, err := g.generate(items, defaultItem, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		numItems := 1
+		if minItems, ok := s["minItems"].(float64); ok && int(minItems) > numItems {
+			numItems = int(minItems)
+		}
+		arr := make([]interface{}, numItems)
+		for i := range arr {
+			arr[i] = item
+		}
+		return arr, nil
+	case "object":
+		return g.generateObject(s, defaultValue, depth)
+	}
+	return inferValue(defaultValue), nil
+}
+
+func (g *valueGenerator) generateObject(s map[string]interface{}, defaultValue interface{}, depth int) (interface{}, error) {
+	defaultObject, _ := defaultValue.(map[string]interface{})
+	properties, _ := s["properties"].(map[string]interface{})
+	var keys []string
+	if required, ok := s["required"].([]interface{}); ok {
+		for _, key := range required {
+			if k, ok := key.(string); ok {
+				keys = append(keys, k)
+			}
+		}
+	}
+	if len(keys) == 0 && len(properties) > 0 {
+		// set at least one property so that the object is not empty
+		for key := range properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		keys = keys[:1]
+	}
+	obj := map[string]interface{}{}
+	for _, key := range keys {
+		propertySchema, _ := properties[key].(map[string]interface{})
+		value, err := g.generate(propertySchema, defaultObject[key], depth+1)
+		if err != nil {
+			return nil, err
+		}
+		obj[key] = value
+	}
+	if len(obj) > 0 {
+		return obj, nil
+	}
+	additionalProperties, ok := s["additionalProperties"].(map[string]interface{})
+	if !ok {
+		if allowed, ok := s["additionalProperties"].(bool); ok && !allowed {
+			return obj, nil
+		}
+		return inferValue(defaultValue), nil
+	}
+	value, err := g.generate(additionalProperties, nil, depth+1)
+	if err != nil {
+		return nil, err
+	}
+	obj["hull"] = value
+	return obj, nil
+}
+
+func generateNumber(s map[string]interface{}, defaultValue interface{}) (interface{}, error) {
+	isInteger := schemaType(s) == "integer"
+	minimum, hasMinimum := s["minimum"].(float64)
+	maximum, hasMaximum := s["maximum"].(float64)
+	exclusiveMinimum, hasExclusiveMinimum := s["exclusiveMinimum"].(float64)
+	exclusiveMaximum, hasExclusiveMaximum := s["exclusiveMaximum"].(float64)
+	multipleOf, hasMultipleOf := s["multipleOf"].(float64)
+	valid := func(n float64) bool {
+		switch {
+		case isInteger && n != math.Trunc(n):
+			return false
+		case hasMinimum && n < minimum, hasMaximum && n > maximum:
+			return false
+		case hasExclusiveMinimum && n <= exclusiveMinimum, hasExclusiveMaximum && n >= exclusiveMaximum:
+			return false
+		case hasMultipleOf && multipleOf != 0 && n/multipleOf != math.Trunc(n/multipleOf):
+			return false
+		}
+		return true
+	}
+	defaultNumber, hasDefault := defaultValue.(float64)
+	var candidates []float64
+	if hasDefault {
+		candidates = append(candidates, defaultNumber+1, defaultNumber-1)
+	}
+	if hasMultipleOf {
+		candidates = append(candidates, multipleOf, 2*multipleOf)
+	}
+	candidates = append(candidates, minimum, maximum, exclusiveMinimum+1, exclusiveMaximum-1, 1, 2, 0)
+	for _, candidate := range candidates {
+		if hasDefault && candidate == defaultNumber {
+			continue
+		}
+		if valid(candidate) {
+			if isInteger {
+				return int64(candidate), nil
+			}
+			return candidate, nil
+		}
+	}
+	return nil, fmt.Errorf("unable to generate a number that satisfies the schema")
+}
+
+func generateString(s map[string]interface{}, defaultValue interface{}) (interface{}, error) {
+	var candidates []string
+	if f, ok := s["format"].(string); ok {
+		if candidate, ok := generatedFormatCandidates[f]; ok {
+			candidates = append(candidates, candidate)
+		}
+	}
+	candidates = append(candidates, generatedStringCandidates...)
+	var pattern *regexp.Regexp
+	if p, ok := s["pattern"].(string); ok {
+		var err error
+		pattern, err = regexp.Compile(p)
+		if err != nil {
+			return generatedString, fmt.Errorf("unable to parse pattern %s: %s", p, err)
+		}
+	}
+	minLength, hasMinLength := s["minLength"].(float64)
+	maxLength, hasMaxLength := s["maxLength"].(float64)
+	for _, candidate := range candidates {
+		if candidate == defaultValue {
+			continue
+		}
+		if hasMinLength && float64(len(candidate)) < minLength {
+			candidate += strings.Repeat("a", int(minLength)-len(candidate))
+		}
+		if hasMaxLength && float64(len(candidate)) > maxLength {
+			continue
+		}
+		if pattern != nil && !pattern.MatchString(candidate) {
+			continue
+		}
+		return candidate, nil
+	}
+	if pattern != nil {
+		return generatedString, fmt.Errorf("unable to generate a string that matches pattern %s; replace %q with a valid value", pattern, generatedString)
+	}
+	return generatedString, fmt.Errorf("unable to generate a string that satisfies the schema; replace %q with a valid value", generatedString)
+}
+
+// inferValue returns a value of the same type as the provided default value that differs from it
+func inferValue(defaultValue interface{}) interface{} {
+	switch val := defaultValue.(type) {
+	case bool:
+		return !val
+	case float64:
+		if val == math.Trunc(val) {
+			return int64(val) + 1
+		}
+		return val + 1
+	case int64:
+		return val + 1
+	case int:
+		return val + 1
+	case string:
+		if val == generatedString {
+			return "hull"
+		}
+		return generatedString
+	case []interface{}:
+		if len(val) > 0 {
+			return []interface{}{inferValue(val[0])}
+		}
+		return []interface{}{generatedString}
+	case map[string]interface{}:
+		obj := map[string]interface{}{}
+		for k, v := range val {
+			obj[k] = inferValue(v)
+		}
+		if len(obj) == 0 {
+			obj["hull"] = generatedString
+		}
+		return obj
+	}
+	return generatedString
+}
+
+func schemaType(s map[string]interface{}) string {
+	switch t := s["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, typ := range t {
+			if str, ok := typ.(string); ok && str != "null" {
+				return str
+			}
+		}
+	}
+	return ""
+}
+
+func pickDifferent(values []interface{}, defaultValue interface{}) interface{} {
+	defaultJSON, _ := json.Marshal(defaultValue)
+	for _, value := range values {
+		valueJSON, _ := json.Marshal(value)
+		if !bytes.Equal(defaultJSON, valueJSON) {
+			return value
+		}
+	}
+	return values[0]
+}
+
+func toCaseNameValue(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+func toGoLiteral(value interface{}) string {
+	switch val := value.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return fmt.Sprintf("%q", val)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var entries []string
+		for _, k := range keys {
+			entries = append(entries, fmt.Sprintf("%q: %s", k, toGoLiteral(val[k])))
+		}
+		return fmt.Sprintf("map[string]interface{}{%s}", strings.Join(entries, ", "))
+	case []interface{}:
+		var items []string
+		for _, item := range val {
+			items = append(items, toGoLiteral(item))
+		}
+		return fmt.Sprintf("[]interface{}{%s}", strings.Join(items, ", "))
+	}
+	return fmt.Sprintf("%v", value)
+}
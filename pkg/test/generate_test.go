@@ -0,0 +1,256 @@
+package test
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/rancher/hull/pkg/chart"
+	"github.com/rancher/hull/pkg/checker"
+	"github.com/rancher/hull/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	generateChartPath = utils.MustGetPathFromModuleRoot("testdata", "charts", "generate-chart")
+)
+
+func TestGenerateCases(t *testing.T) {
+	testCases := []struct {
+		Name             string
+		ChartPath        string
+		Fields           []string
+		ShouldThrowError bool
+		ExpectValue      interface{}
+		ExpectName       string
+		ExpectWarning    bool
+	}{
+		{
+			Name:        "Integer Within Range",
+			ChartPath:   generateChartPath,
+			Fields:      []string{".Values.replicas"},
+			ExpectValue: int64(2),
+			ExpectName:  "Set .Values.replicas to 2",
+		},
+		{
+			Name:        "Negated Boolean",
+			ChartPath:   generateChartPath,
+			Fields:      []string{".Values.debug"},
+			ExpectValue: true,
+			ExpectName:  "Set .Values.debug to true",
+		},
+		{
+			Name:        "Enum",
+			ChartPath:   generateChartPath,
+			Fields:      []string{".Values.logLevel"},
+			ExpectValue: "debug",
+			ExpectName:  "Set .Values.logLevel to debug",
+		},
+		{
+			Name:        "String Matching Pattern Through Reference",
+			ChartPath:   generateChartPath,
+			Fields:      []string{".Values.image.repository"},
+			ExpectValue: "hull-test",
+			ExpectName:  "Set .Values.image.repository to hull-test",
+		},
+		{
+			Name:          "String Not Matching Pattern",
+			ChartPath:     generateChartPath,
+			Fields:        []string{".Values.image.tag"},
+			ExpectValue:   "hull-test",
+			ExpectName:    "Set .Values.image.tag to hull-test",
+			ExpectWarning: true,
+		},
+		{
+			Name:        "Object Through Reference",
+			ChartPath:   generateChartPath,
+			Fields:      []string{".Values.image"},
+			ExpectValue: map[string]interface{}{"repository": "hull-test"},
+			ExpectName:  `Set .Values.image to {"repository":"hull-test"}`,
+		},
+		{
+			Name:        "Map Without Schema",
+			ChartPath:   generateChartPath,
+			Fields:      []string{".Values.extraLabels"},
+			ExpectValue: map[string]interface{}{"hull": "hull-test"},
+			ExpectName:  `Set .Values.extraLabels to {"hull":"hull-test"}`,
+		},
+		{
+			Name:        "List Without Schema",
+			ChartPath:   generateChartPath,
+			Fields:      []string{".Values.args"},
+			ExpectValue: []interface{}{"hull-test"},
+			ExpectName:  `Set .Values.args to ["hull-test"]`,
+		},
+		{
+			Name:        "Chart Without Schema",
+			ChartPath:   schemaChartPath,
+			Fields:      []string{".Values.replicas"},
+			ExpectValue: int64(2),
+			ExpectName:  "Set .Values.replicas to 2",
+		},
+		{
+			Name:        "Object Without Schema",
+			ChartPath:   schemaChartPath,
+			Fields:      []string{".Values.widget"},
+			ExpectValue: map[string]interface{}{"color": "hull-test", "port": "hull-test", "size": "hull-test"},
+			ExpectName:  `Set .Values.widget to {"color":"hull-test","port":"hull-test","size":"hull-test"}`,
+		},
+		{
+			Name:             "Not A Values Field",
+			ChartPath:        generateChartPath,
+			Fields:           []string{".Release.Name"},
+			ShouldThrowError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			c, err := chart.NewChart(tc.ChartPath)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			cases, err := GenerateCases(c, tc.Fields)
+			if tc.ShouldThrowError {
+				assert.NotNil(t, err)
+				return
+			}
+			if !assert.Nil(t, err) || !assert.Len(t, cases, 1) {
+				return
+			}
+			assert.Equal(t, tc.Fields[0], cases[0].Field)
+			assert.Equal(t, tc.ExpectValue, cases[0].Value)
+			assert.Equal(t, tc.ExpectName, cases[0].Name)
+			if tc.ExpectWarning {
+				assert.NotEmpty(t, cases[0].Warning)
+			} else {
+				assert.Empty(t, cases[0].Warning)
+			}
+		})
+	}
+}
+
+func TestSuiteUncoveredFields(t *testing.T) {
+	s := &Suite{
+		ChartPath:     generateChartPath,
+		DefaultValues: chart.NewValues().Set("image.tag", "latest"),
+		Cases: []Case{
+			{
+				Name:            "Set Replicas",
+				TemplateOptions: chart.NewTemplateOptions("generate-chart", "default").Set("replicas", 2),
+			},
+			{
+				Name:            "Set Debug",
+				TemplateOptions: chart.NewTemplateOptions("generate-chart", "default").Set("debug", true),
+				OmitNamedChecks: []string{"Debug"},
+			},
+		},
+		NamedChecks: []NamedCheck{
+			{
+				Name:   "Replicas",
+				Checks: Checks{checker.Once(func(*checker.TestContext) {})},
+				Covers: []string{".Values.replicas"},
+			},
+			{
+				Name:   "Debug",
+				Checks: Checks{checker.Once(func(*checker.TestContext) {})},
+				Covers: []string{".Values.debug"},
+			},
+		},
+	}
+	fields, err := s.UncoveredFields(false)
+	if !assert.Nil(t, err) {
+		return
+	}
+	// the DefaultValues are not merged into the Cases of the Suite
+	assert.Len(t, s.Cases[0].TemplateOptions.Values.JSONValues, 1)
+	assert.Equal(t, []string{
+		".Values.args",
+		".Values.debug",
+		".Values.extraLabels",
+		".Values.image.repository",
+		".Values.image.tag",
+		".Values.logLevel",
+	}, fields)
+}
+
+func TestFormatCases(t *testing.T) {
+	c, err := chart.NewChart(generateChartPath)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	cases, err := GenerateCases(c, []string{".Values.replicas", ".Values.image.tag", ".Values.extraLabels"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("Go", func(t *testing.T) {
+		src, err := FormatCasesAsGo(cases)
+		if !assert.Nil(t, err) {
+			return
+		}
+		for _, expect := range []string{
+			`chart.NewTemplateOptions("generate-chart", "default").Set("replicas", 2)`,
+			`// TODO: `,
+			`.Set("extraLabels", map[string]interface{}{"hull": "hull-test"})`,
+		} {
+			assert.Contains(t, string(src), expect)
+		}
+		assert.Nil(t, typeCheck(src))
+	})
+
+	t.Run("Suite", func(t *testing.T) {
+		data, err := FormatCasesAsSuite(cases)
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.True(t, strings.HasPrefix(string(data), "#"))
+		var suiteFile SuiteFile
+		if !assert.Nil(t, yaml.Unmarshal(data, &suiteFile)) || !assert.Len(t, suiteFile.Cases, 3) {
+			return
+		}
+		assert.Equal(t, "Set .Values.replicas to 2", suiteFile.Cases[0].Name)
+		assert.Equal(t, "generate-chart", suiteFile.Cases[0].ReleaseName)
+		assert.Equal(t, float64(2), suiteFile.Cases[0].SetJSON["replicas"])
+	})
+}
+
+// typeCheck type checks the Go source against the export data of the packages it imports
+func typeCheck(src []byte) error {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "generated.go", src, 0)
+	if err != nil {
+		return err
+	}
+	args := []string{"list", "-export", "-deps", "-f", "{{.ImportPath}}={{.Export}}"}
+	for _, spec := range f.Imports {
+		args = append(args, strings.Trim(spec.Path.Value, `"`))
+	}
+	out, err := exec.Command("go", args...).Output()
+	if err != nil {
+		return err
+	}
+	exports := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		path, export, _ := strings.Cut(line, "=")
+		exports[path] = export
+	}
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "gc", func(path string) (io.ReadCloser, error) {
+			return os.Open(exports[path])
+		}),
+	}
+	_, err = conf.Check("generated", fset, []*ast.File{f}, nil)
+	return err
+}
@@ -0,0 +1,222 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/rancher/hull/pkg/chart"
+	"sigs.k8s.io/yaml"
+)
+
+// SuiteFile is the declarative representation of a Suite that can be loaded via LoadSuite
+//
+// A SuiteFile can be written in either YAML or JSON.
+type SuiteFile struct {
+	ChartPath     string            `json:"chartPath,omitempty"`
+	DefaultValues *ValuesFile       `json:"defaultValues,omitempty"`
+	NamedChecks   []NamedCheckFile  `json:"namedChecks,omitempty"`
+	Cases         []CaseFile        `json:"cases,omitempty"`
+	FailureCases  []FailureCaseFile `json:"failureCases,omitempty"`
+}
+
+type NamedCheckFile struct {
+	Name       string      `json:"name"`
+	Covers     []string    `json:"covers,omitempty"`
+	Assertions []Assertion `json:"assertions,omitempty"`
+}
+
+type CaseFile struct {
+	Name string `json:"name"`
+	TemplateOptionsFile
+
+	OmitNamedChecks []string `json:"omitNamedChecks,omitempty"`
+}
+
+type FailureCaseFile struct {
+	Name string `json:"name"`
+	TemplateOptionsFile
+
+	Covers         []string `json:"covers,omitempty"`
+	FailureMessage string   `json:"failureMessage"`
+}
+
+// TemplateOptionsFile is the declarative representation of a chart.TemplateOptions
+type TemplateOptionsFile struct {
+	ReleaseName string `json:"releaseName,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	KubeVersion string `json:"kubeVersion,omitempty"`
+	IsUpgrade   bool   `json:"isUpgrade,omitempty"`
+
+	// KubeRelease sets the kubeVersion and apiVersions to those of a release of Kubernetes (i.e. 1.25)
+	KubeRelease string   `json:"kubeRelease,omitempty"`
+	APIVersions []string `json:"apiVersions,omitempty"`
+	ValuesFile
+}
+
+// ValuesFile is the declarative representation of a chart.Values
+//
+// Each field corresponds to the helm flag of the same name (i.e. ValuesFiles corresponds to -f and SetJSON to --set-json).
+type ValuesFile struct {
+	ValuesFiles []string               `json:"valuesFiles,omitempty"`
+	Set         map[string]string      `json:"set,omitempty"`
+	SetString   map[string]string      `json:"setString,omitempty"`
+	SetFile     map[string]string      `json:"setFile,omitempty"`
+	SetJSON     map[string]interface{} `json:"setJSON,omitempty"`
+}
+
+// LoadSuite reads a SuiteFile from the provided path and converts it into a Suite
+//
+// Relative paths within the SuiteFile are resolved against the directory containing the SuiteFile.
+func LoadSuite(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var suiteFile SuiteFile
+	if err := yaml.UnmarshalStrict(data, &suiteFile); err != nil {
+		return nil, fmt.Errorf("unable to parse suite file %s: %s", path, err)
+	}
+	suite, err := suiteFile.ToSuite(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("invalid suite file %s: %s", path, err)
+	}
+	return suite, nil
+}
+
+// ToSuite converts a SuiteFile into a Suite, resolving relative paths against baseDir
+func (f *SuiteFile) ToSuite(baseDir string) (*Suite, error) {
+	s := &Suite{
+		ChartPath: resolvePath(baseDir, f.ChartPath),
+	}
+	if f.DefaultValues != nil {
+		s.DefaultValues = f.DefaultValues.toValues(baseDir)
+	}
+	checkNames := map[string]bool{}
+	for _, nc := range f.NamedChecks {
+		if len(nc.Name) == 0 {
+			return nil, fmt.Errorf("named check must have a name")
+		}
+		if checkNames[nc.Name] {
+			return nil, fmt.Errorf("found multiple named checks with name %s", nc.Name)
+		}
+		checkNames[nc.Name] = true
+		namedCheck := NamedCheck{
+			Name:   nc.Name,
+			Covers: nc.Covers,
+		}
+		for i, a := range nc.Assertions {
+			if err := a.validate(); err != nil {
+				return nil, fmt.Errorf("assertion %d of named check %s is invalid: %s", i, nc.Name, err)
+			}
+			namedCheck.Checks = append(namedCheck.Checks, a.Check())
+		}
+		s.NamedChecks = append(s.NamedChecks, namedCheck)
+	}
+	caseNames := map[string]bool{}
+	for _, c := range f.Cases {
+		if len(c.Name) == 0 {
+			return nil, fmt.Errorf("case must have a name")
+		}
+		if caseNames[c.Name] {
+			return nil, fmt.Errorf("found multiple cases with name %s", c.Name)
+		}
+		caseNames[c.Name] = true
+		for _, omit := range c.OmitNamedChecks {
+			if !checkNames[omit] {
+				return nil, fmt.Errorf("case %s cannot omit named check %s that does not exist", c.Name, omit)
+			}
+		}
+		templateOptions, err := c.toTemplateOptions(baseDir)
+		if err != nil {
+			return nil, fmt.Errorf("case %s is invalid: %s", c.Name, err)
+		}
+		s.Cases = append(s.Cases, Case{
+			Name:            c.Name,
+			TemplateOptions: templateOptions,
+			OmitNamedChecks: c.OmitNamedChecks,
+		})
+	}
+	for _, c := range f.FailureCases {
+		if len(c.Name) == 0 {
+			return nil, fmt.Errorf("failure case must have a name")
+		}
+		if caseNames[c.Name] {
+			return nil, fmt.Errorf("found multiple cases with name %s", c.Name)
+		}
+		caseNames[c.Name] = true
+		if len(c.FailureMessage) == 0 {
+			return nil, fmt.Errorf("failure case %s must have a failureMessage", c.Name)
+		}
+		templateOptions, err := c.toTemplateOptions(baseDir)
+		if err != nil {
+			return nil, fmt.Errorf("failure case %s is invalid: %s", c.Name, err)
+		}
+		s.FailureCases = append(s.FailureCases, FailureCase{
+			Name:            c.Name,
+			TemplateOptions: templateOptions,
+			Covers:          c.Covers,
+			FailureMessage:  c.FailureMessage,
+		})
+	}
+	return s, nil
+}
+
+func (f TemplateOptionsFile) toTemplateOptions(baseDir string) (opts *chart.TemplateOptions, err error) {
+	defer func() {
+		// SetKubeVersion and WithKubeRelease panic on invalid input
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s", r)
+		}
+	}()
+	opts = chart.NewTemplateOptions(f.ReleaseName, f.Namespace)
+	if len(f.KubeRelease) > 0 {
+		opts.WithKubeRelease(f.KubeRelease)
+	}
+	if len(f.KubeVersion) > 0 {
+		opts.SetKubeVersion(f.KubeVersion)
+	}
+	if len(f.APIVersions) > 0 {
+		opts.SetAPIVersions(f.APIVersions...)
+	}
+	opts.IsUpgrade(f.IsUpgrade)
+	opts.Values = f.ValuesFile.toValues(baseDir)
+	return opts, nil
+}
+
+func (f ValuesFile) toValues(baseDir string) *chart.Values {
+	v := chart.NewValues()
+	for _, valuesFile := range f.ValuesFiles {
+		v.ValueFiles = append(v.ValueFiles, resolvePath(baseDir, valuesFile))
+	}
+	for _, key := range sortedKeys(f.Set) {
+		v = v.SetValue(key, f.Set[key])
+	}
+	for _, key := range sortedKeys(f.SetString) {
+		v.StringValues = append(v.StringValues, fmt.Sprintf("%s=%s", key, f.SetString[key]))
+	}
+	for _, key := range sortedKeys(f.SetFile) {
+		v.FileValues = append(v.FileValues, fmt.Sprintf("%s=%s", key, resolvePath(baseDir, f.SetFile[key])))
+	}
+	for _, key := range sortedKeys(f.SetJSON) {
+		v = v.Set(key, f.SetJSON[key])
+	}
+	return v
+}
+
+func resolvePath(baseDir, path string) string {
+	if len(path) == 0 || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
+
+func sortedKeys[V interface{}](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,312 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rancher/hull/pkg/chart"
+	"github.com/rancher/hull/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	helmChartUtil "helm.sh/helm/v3/pkg/chartutil"
+)
+
+var (
+	simpleSuitePath     = utils.MustGetPathFromModuleRoot("testdata", "suites", "simple-chart.yaml")
+	simpleJSONSuitePath = utils.MustGetPathFromModuleRoot("testdata", "suites", "simple-chart.json")
+)
+
+func TestLoadSuite(t *testing.T) {
+	testCases := []struct {
+		Name               string
+		Contents           string
+		ShouldThrowError   bool
+		ExpectChartPath    string
+		ExpectCases        []string
+		ExpectFailureCases []string
+		ExpectChecks       []string
+	}{
+		{
+			Name:     "Empty",
+			Contents: "",
+		},
+		{
+			Name: "Relative Chart Path",
+			Contents: `
+chartPath: ../charts/simple-chart
+`,
+			ExpectChartPath: filepath.Join("..", "charts", "simple-chart"),
+		},
+		{
+			Name: "Absolute Chart Path",
+			Contents: `
+chartPath: /charts/simple-chart
+`,
+			ExpectChartPath: "/charts/simple-chart",
+		},
+		{
+			Name: "Cases And Checks",
+			Contents: `
+cases:
+- name: Using Defaults
+- name: Set Value
+  kubeVersion: v1.25.0
+  set:
+    hello: world
+namedChecks:
+- name: Count
+  assertions:
+  - count: 1
+`,
+			ExpectCases:  []string{"Using Defaults", "Set Value"},
+			ExpectChecks: []string{"Count"},
+		},
+		{
+			Name: "Failure Cases",
+			Contents: `
+failureCases:
+- name: Should Fail
+  set:
+    shouldFail: "true"
+  covers:
+  - .Values.shouldFail
+  failureMessage: failed
+`,
+			ExpectFailureCases: []string{"Should Fail"},
+		},
+		{
+			Name:        "JSON",
+			Contents:    `{"cases": [{"name": "Using Defaults", "setJSON": {"hello": {"world": true}}}]}`,
+			ExpectCases: []string{"Using Defaults"},
+		},
+		{
+			Name: "Omit Named Check",
+			Contents: `
+namedChecks:
+- name: hello
+cases:
+- name: world
+  omitNamedChecks:
+  - hello
+`,
+			ExpectCases:  []string{"world"},
+			ExpectChecks: []string{"hello"},
+		},
+		{
+			Name: "Omit Missing Named Check",
+			Contents: `
+cases:
+- name: world
+  omitNamedChecks:
+  - hello
+`,
+			ShouldThrowError: true,
+		},
+		{
+			Name: "Failure Case Without Message",
+			Contents: `
+failureCases:
+- name: Should Fail
+`,
+			ShouldThrowError: true,
+		},
+		{
+			Name: "Failure Case With Same Name As Case",
+			Contents: `
+cases:
+- name: hello
+failureCases:
+- name: hello
+  failureMessage: failed
+`,
+			ShouldThrowError: true,
+		},
+		{
+			Name: "Unknown Field",
+			Contents: `
+chartPaths: ../charts/simple-chart
+`,
+			ShouldThrowError: true,
+		},
+		{
+			Name: "Unnamed Case",
+			Contents: `
+cases:
+- set:
+    hello: world
+`,
+			ShouldThrowError: true,
+		},
+		{
+			Name: "Duplicate Case",
+			Contents: `
+cases:
+- name: hello
+- name: hello
+`,
+			ShouldThrowError: true,
+		},
+		{
+			Name: "Invalid Kube Version",
+			Contents: `
+cases:
+- name: hello
+  kubeVersion: not-a-version
+`,
+			ShouldThrowError: true,
+		},
+		{
+			Name: "Invalid Kube Release",
+			Contents: `
+cases:
+- name: hello
+  kubeRelease: "1.15"
+`,
+			ShouldThrowError: true,
+		},
+		{
+			Name: "Duplicate Named Check",
+			Contents: `
+namedChecks:
+- name: hello
+- name: hello
+`,
+			ShouldThrowError: true,
+		},
+		{
+			Name: "Invalid Assertion",
+			Contents: `
+namedChecks:
+- name: hello
+  assertions:
+  - kind: ConfigMap
+`,
+			ShouldThrowError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "hull-suite.yaml")
+			if err := os.WriteFile(path, []byte(tc.Contents), 0644); err != nil {
+				t.Error(err)
+				return
+			}
+			suite, err := LoadSuite(path)
+			if tc.ShouldThrowError {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			expectChartPath := tc.ExpectChartPath
+			if len(expectChartPath) > 0 && !filepath.IsAbs(expectChartPath) {
+				expectChartPath = filepath.Join(dir, expectChartPath)
+			}
+			assert.Equal(t, expectChartPath, suite.ChartPath)
+			var cases, failureCases, checks []string
+			for _, c := range suite.Cases {
+				cases = append(cases, c.Name)
+			}
+			for _, c := range suite.FailureCases {
+				failureCases = append(failureCases, c.Name)
+			}
+			for _, c := range suite.NamedChecks {
+				checks = append(checks, c.Name)
+			}
+			assert.Equal(t, tc.ExpectCases, cases)
+			assert.Equal(t, tc.ExpectFailureCases, failureCases)
+			assert.Equal(t, tc.ExpectChecks, checks)
+		})
+	}
+
+	t.Run("Missing File", func(t *testing.T) {
+		_, err := LoadSuite(filepath.Join(t.TempDir(), "does-not-exist.yaml"))
+		assert.Error(t, err)
+	})
+
+	t.Run("Values", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "hull-suite.json")
+		contents := `{
+	"defaultValues": {"set": {"a": "b"}},
+	"cases": [{
+		"name": "hello",
+		"releaseName": "my-release",
+		"namespace": "my-namespace",
+		"kubeVersion": "1.25.0",
+		"isUpgrade": true,
+		"valuesFiles": ["values.yaml", "/abs/values.yaml"],
+		"set": {"d": "e", "c": "d"},
+		"setString": {"e": "1"},
+		"setFile": {"f": "file.txt"},
+		"setJSON": {"g": {"h": [1, 2]}}
+	}]
+}`
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Error(err)
+			return
+		}
+		suite, err := LoadSuite(path)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, &chart.Values{Values: []string{"a=b"}}, suite.DefaultValues)
+		if !assert.Len(t, suite.Cases, 1) {
+			return
+		}
+		opts := suite.Cases[0].TemplateOptions
+		assert.Equal(t, "my-release", opts.Release.Name)
+		assert.Equal(t, "my-namespace", opts.Release.Namespace)
+		assert.True(t, opts.Release.IsUpgrade)
+		assert.Equal(t, "v1.25.0", opts.Capabilities.KubeVersion.Version)
+		assert.Equal(t, &chart.Values{
+			ValueFiles:   []string{filepath.Join(dir, "values.yaml"), "/abs/values.yaml"},
+			Values:       []string{"c=d", "d=e"},
+			StringValues: []string{"e=1"},
+			FileValues:   []string{"f=" + filepath.Join(dir, "file.txt")},
+			JSONValues:   []string{`g={"h":[1,2]}`},
+		}, opts.Values)
+	})
+
+	t.Run("Capabilities", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "hull-suite.yaml")
+		contents := `
+cases:
+- name: Kubernetes 1.24
+  kubeRelease: "1.24"
+- name: Custom APIVersions
+  kubeVersion: v1.28.0
+  apiVersions:
+  - monitoring.coreos.com/v1
+  - monitoring.coreos.com/v1/ServiceMonitor
+`
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Error(err)
+			return
+		}
+		suite, err := LoadSuite(path)
+		if !assert.NoError(t, err) || !assert.Len(t, suite.Cases, 2) {
+			return
+		}
+		capabilities := suite.Cases[0].TemplateOptions.Capabilities
+		assert.Equal(t, "v1.24.0", capabilities.KubeVersion.Version)
+		assert.True(t, capabilities.APIVersions.Has("policy/v1beta1/PodSecurityPolicy"))
+		capabilities = suite.Cases[1].TemplateOptions.Capabilities
+		assert.Equal(t, "v1.28.0", capabilities.KubeVersion.Version)
+		assert.Equal(t, helmChartUtil.VersionSet{"monitoring.coreos.com/v1", "monitoring.coreos.com/v1/ServiceMonitor"}, capabilities.APIVersions)
+	})
+
+	for _, path := range []string{simpleSuitePath, simpleJSONSuitePath} {
+		t.Run("Run "+filepath.Base(path), func(t *testing.T) {
+			suite, err := LoadSuite(path)
+			if !assert.NoError(t, err) {
+				return
+			}
+			suite.Run(t, nil)
+		})
+	}
+}
@@ -1,16 +1,27 @@
 package test
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/rancher/hull/pkg/chart"
 	"github.com/rancher/hull/pkg/checker"
 	"github.com/rancher/hull/pkg/test/coverage"
 	"github.com/rancher/hull/pkg/tpl"
+	"github.com/rancher/hull/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
 
+const (
+	updateSnapshotsEnvVar = "UPDATE_SNAPSHOTS"
+)
+
 var executionErrorRe = regexp.MustCompile(`execution error at \(.*\): (?P<inner>.*)`)
 
 type Suite struct {
@@ -36,30 +47,54 @@ type FailureCase struct {
 	FailureMessage string
 }
 
+// setDefaults returns a copy of the Suite whose Cases and FailureCases have TemplateOptions with the DefaultValues
+// merged in, leaving the Suite unmodified
 func (s *Suite) setDefaults() *Suite {
-	for i := range s.Cases {
-		if s.Cases[i].TemplateOptions == nil {
-			s.Cases[i].TemplateOptions = &chart.TemplateOptions{}
-		}
-		if s.Cases[i].TemplateOptions.Values == nil {
-			s.Cases[i].TemplateOptions.Values = chart.NewValues()
-		}
-		if s.DefaultValues != nil {
-			s.Cases[i].TemplateOptions.Values = s.DefaultValues.MergeValues(s.Cases[i].TemplateOptions.Values)
-		}
+	suite := *s
+	suite.Cases = make([]Case, len(s.Cases))
+	for i, tc := range s.Cases {
+		tc.TemplateOptions = withDefaultValues(tc.TemplateOptions, s.DefaultValues)
+		suite.Cases[i] = tc
 	}
-	for i := range s.FailureCases {
-		if s.FailureCases[i].TemplateOptions == nil {
-			s.FailureCases[i].TemplateOptions = &chart.TemplateOptions{}
-		}
-		if s.FailureCases[i].TemplateOptions.Values == nil {
-			s.FailureCases[i].TemplateOptions.Values = chart.NewValues()
+	suite.FailureCases = make([]FailureCase, len(s.FailureCases))
+	for i, tc := range s.FailureCases {
+		tc.TemplateOptions = withDefaultValues(tc.TemplateOptions, s.DefaultValues)
+		suite.FailureCases[i] = tc
+	}
+	return &suite
+}
+
+func withDefaultValues(templateOptions *chart.TemplateOptions, defaultValues *chart.Values) *chart.TemplateOptions {
+	if templateOptions == nil {
+		templateOptions = &chart.TemplateOptions{}
+	} else {
+		copied := *templateOptions
+		templateOptions = &copied
+	}
+	if templateOptions.Values == nil {
+		templateOptions.Values = chart.NewValues()
+	}
+	if defaultValues != nil {
+		templateOptions.Values = defaultValues.MergeValues(templateOptions.Values)
+	}
+	return templateOptions
+}
+
+// namedChecksFor returns the NamedChecks that run against the Case, which are every NamedCheck not omitted by it
+func (s *Suite) namedChecksFor(tc Case) []NamedCheck {
+	var namedChecks []NamedCheck
+	for _, check := range s.NamedChecks {
+		var skip bool
+		for _, omitCase := range tc.OmitNamedChecks {
+			if check.Name == omitCase {
+				skip = true
+			}
 		}
-		if s.DefaultValues != nil {
-			s.FailureCases[i].TemplateOptions.Values = s.DefaultValues.MergeValues(s.FailureCases[i].TemplateOptions.Values)
+		if !skip {
+			namedChecks = append(namedChecks, check)
 		}
 	}
-	return s
+	return namedChecks
 }
 
 func GetRancherOptions() *SuiteOptions {
@@ -76,6 +111,13 @@ type SuiteOptions struct {
 	HelmLint *chart.HelmLintOptions
 	YAMLLint YamlLintOptions
 	Coverage CoverageOptions
+	Snapshot SnapshotOptions
+	Upgrade  UpgradeOptions
+
+	SchemaValidation SchemaValidationOptions
+	KubeVersions     KubeVersionOptions
+	DeprecatedAPIs   DeprecatedAPIOptions
+	StrictDecoding   StrictDecodingOptions
 }
 
 type YamlLintOptions struct {
@@ -88,6 +130,154 @@ type CoverageOptions struct {
 	Disabled         bool
 }
 
+// SnapshotOptions configures comparing the rendered templates of each Case against a committed snapshot
+//
+// Snapshots are stored under Directory/<suite>/<case>/<template>, where <suite> is the name of the test that
+// runs the Suite and <case> is the name of the Case with spaces replaced by underscores. Snapshots are written instead of compared
+// if Update is set or if the tests are run with the UPDATE_SNAPSHOTS environment variable set to true.
+type SnapshotOptions struct {
+	Enabled   bool
+	Directory string
+	Update    bool
+}
+
+// UpgradeOptions configures checking whether each Case can be upgraded to from a previous version of the chart
+//
+// For every Case, the previous version of the chart is rendered with the Case's TemplateOptions and the current
+// chart is rendered as an upgrade with the same values; the check fails if the upgrade modifies any immutable fields.
+type UpgradeOptions struct {
+	Enabled bool
+
+	// PreviousChartPath is the path to the previous version of the chart (either a directory or a packaged chart)
+	PreviousChartPath string
+
+	// IndexPath is the path, relative to the root of the Go module, of a Helm repository index.yaml that is
+	// used to find the latest version of the chart if PreviousChartPath is not provided
+	IndexPath         string
+	IncludePrerelease bool
+}
+
+// SchemaValidationOptions configures validating every rendered object against the OpenAPI schemas for the Case's KubeVersion
+//
+// Built-in resources are validated against the Kubernetes schemas bundled into Hull for the nearest minor version of the
+// KubeVersion, unless KubernetesSchemaPath is provided.
+// Custom resources are validated against the CRDs shipped in the chart, rendered by the chart, or found in CRDChartPaths
+// or CRDPaths; custom resources without a CRD are skipped.
+type SchemaValidationOptions struct {
+	Enabled bool
+
+	KubernetesSchemaPath string
+	CRDChartPaths        []string
+	CRDPaths             []string
+}
+
+func (o SchemaValidationOptions) toSchemaOptions() *chart.SchemaOptions {
+	return &chart.SchemaOptions{
+		KubernetesSchemaPath: o.KubernetesSchemaPath,
+		CRDChartPaths:        o.CRDChartPaths,
+		CRDPaths:             o.CRDPaths,
+	}
+}
+
+// DeprecatedAPIOptions configures checking that every rendered object uses a built-in API that is served by the Case's KubeVersion
+//
+// Objects that use APIs that are no longer (or not yet) served fail the check; objects that use deprecated APIs are logged
+// as warnings unless FailOnDeprecated is set. Cases that do not set a KubeVersion are checked against Helm's default
+// Capabilities (v1.20.0), so enable KubeVersions or set the KubeVersion of each Case when enabling this check.
+type DeprecatedAPIOptions struct {
+	Enabled          bool
+	FailOnDeprecated bool
+}
+
+// StrictDecodingOptions configures failing each Case on rendered objects that its NamedChecks silently ignore
+//
+// A Case fails if any non-empty field of a rendered object is dropped when it is decoded into its Go type (i.e. a
+// misspelled or misplaced key) or, unless AllowUnchecked is set, if a rendered object is not accepted by any NamedCheck.
+type StrictDecodingOptions struct {
+	Enabled        bool
+	AllowUnchecked bool
+}
+
+// KubeVersionOptions configures running every Case against multiple versions of Kubernetes
+//
+// Each Case runs as a subtest per version with Capabilities.KubeVersion set to that version and Capabilities.APIVersions
+// set to the APIs served by that version. If Versions is not provided, every version supported by Hull that satisfies
+// the chart's kubeVersion (or the catalog.cattle.io/kube-version annotation) is used. Cases that set their own
+// Capabilities are only run once.
+type KubeVersionOptions struct {
+	Enabled  bool
+	Versions []string
+}
+
+func (o KubeVersionOptions) getKubeVersions(c chart.Chart) ([]string, error) {
+	if len(o.Versions) > 0 {
+		for _, kubeVersion := range o.Versions {
+			if _, err := semver.NewVersion(kubeVersion); err != nil {
+				return nil, fmt.Errorf("invalid kubeVersion %s: %s", kubeVersion, err)
+			}
+		}
+		return o.Versions, nil
+	}
+	metadata := c.GetHelmChart().Metadata
+	constraintSource := "kubeVersion"
+	constraintString := metadata.KubeVersion
+	if len(constraintString) == 0 {
+		constraintSource = "annotation catalog.cattle.io/kube-version"
+		constraintString = metadata.Annotations["catalog.cattle.io/kube-version"]
+	}
+	if len(constraintString) == 0 {
+		return nil, fmt.Errorf("must provide KubeVersions.Versions since chart %s does not set a kubeVersion or the catalog.cattle.io/kube-version annotation", metadata.Name)
+	}
+	constraint, err := semver.NewConstraint(constraintString)
+	if err != nil {
+		return nil, fmt.Errorf("chart %s has an invalid %s %s: %s", metadata.Name, constraintSource, constraintString, err)
+	}
+	var kubeVersions []string
+	for _, kubeVersion := range chart.SupportedKubeVersions() {
+		if constraint.Check(semver.MustParse(kubeVersion)) {
+			kubeVersions = append(kubeVersions, kubeVersion)
+		}
+	}
+	if len(kubeVersions) == 0 {
+		return nil, fmt.Errorf("no supported version of Kubernetes satisfies %s %s of chart %s", constraintSource, constraintString, metadata.Name)
+	}
+	return kubeVersions, nil
+}
+
+func withKubeVersion(templateOptions *chart.TemplateOptions, kubeVersion string) (*chart.TemplateOptions, error) {
+	apiVersions, err := chart.KubeAPIVersions(kubeVersion)
+	if err != nil {
+		return nil, err
+	}
+	kubeVersionOptions := *templateOptions
+	kubeVersionOptions.Capabilities = nil
+	kubeVersionOptions.SetKubeVersion(kubeVersion).SetAPIVersions(apiVersions...)
+	return &kubeVersionOptions, nil
+}
+
+func (o UpgradeOptions) getPreviousChart(chartName string) (chart.Chart, error) {
+	previousChartPath := o.PreviousChartPath
+	if len(previousChartPath) == 0 {
+		if len(o.IndexPath) == 0 {
+			return nil, fmt.Errorf("must provide either a PreviousChartPath or an IndexPath to run upgrade checks")
+		}
+		var err error
+		previousChartPath, err = utils.GetLatestChartVersionPathFromIndex(o.IndexPath, chartName, o.IncludePrerelease)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find previous version of chart %s in %s: %s", chartName, o.IndexPath, err)
+		}
+	}
+	return chart.NewChart(previousChartPath)
+}
+
+func (o SnapshotOptions) shouldUpdate() bool {
+	if o.Update {
+		return true
+	}
+	update, _ := strconv.ParseBool(os.Getenv(updateSnapshotsEnvVar))
+	return update
+}
+
 func (o *SuiteOptions) setDefaults() *SuiteOptions {
 	if o == nil {
 		o = &SuiteOptions{}
@@ -98,6 +288,9 @@ func (o *SuiteOptions) setDefaults() *SuiteOptions {
 	if len(o.YAMLLint.Configuration) == 0 {
 		o.YAMLLint.Configuration = chart.DefaultYamllintConf
 	}
+	if len(o.Snapshot.Directory) == 0 {
+		o.Snapshot.Directory = filepath.Join("testdata", "__snapshots__")
+	}
 	return o
 }
 
@@ -119,14 +312,33 @@ func (s *Suite) Run(t *testing.T, opts *SuiteOptions) {
 		return
 	}
 	coverageTracker := coverage.NewTracker(templateUsage, opts.Coverage.IncludeSubcharts)
+	suiteName := t.Name()
+	var previousChart chart.Chart
+	if opts.Upgrade.Enabled {
+		previousChart, err = opts.Upgrade.getPreviousChart(c.GetHelmChart().Name())
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	var kubeVersions []string
+	if opts.KubeVersions.Enabled {
+		kubeVersions, err = opts.KubeVersions.getKubeVersions(c)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	// the CRD charts used to validate schemas are only loaded once for every case
+	schemaOptions := opts.SchemaValidation.toSchemaOptions()
 	for _, tc := range s.Cases {
-		t.Run(tc.Name, func(t *testing.T) {
-			template, err := c.RenderTemplate(tc.TemplateOptions)
+		runCase := func(t *testing.T, templateOptions *chart.TemplateOptions, kubeVersionDir string) {
+			template, err := c.RenderTemplate(templateOptions)
 			if err != nil {
 				t.Errorf("failed to render template: %s", err)
 				return
 			}
-			renderValues, err := c.RenderValues(tc.TemplateOptions)
+			renderValues, err := c.RenderValues(templateOptions)
 			if err != nil {
 				t.Errorf("failed to render values: %s", err)
 				return
@@ -149,29 +361,76 @@ func (s *Suite) Run(t *testing.T, opts *SuiteOptions) {
 					template.YamlLint(t, opts.YAMLLint.Configuration)
 				})
 			}
-			for _, check := range s.NamedChecks {
-				// skip cases if necessary
-				var skip bool
-				for _, omitCase := range tc.OmitNamedChecks {
-					if check.Name == omitCase {
-						skip = true
+			if opts.SchemaValidation.Enabled {
+				t.Run("SchemaValidation", func(t *testing.T) {
+					template.ValidateSchema(t, schemaOptions)
+				})
+			}
+			if opts.DeprecatedAPIs.Enabled {
+				t.Run("DeprecatedAPIs", func(t *testing.T) {
+					template.CheckDeprecatedAPIs(t, &chart.DeprecatedAPIOptions{
+						FailOnDeprecated: opts.DeprecatedAPIs.FailOnDeprecated,
+					})
+				})
+			}
+			if opts.Snapshot.Enabled {
+				snapshotDir := filepath.Join(opts.Snapshot.Directory, filepath.FromSlash(suiteName), strings.ReplaceAll(tc.Name, " ", "_"), kubeVersionDir)
+				t.Run("Snapshot", func(t *testing.T) {
+					template.Snapshot(t, snapshotDir, opts.Snapshot.shouldUpdate())
+				})
+			}
+			if previousChart != nil {
+				t.Run("Upgrade", func(t *testing.T) {
+					previousTemplate, err := previousChart.RenderTemplate(templateOptions)
+					if err != nil {
+						t.Errorf("failed to render template for previous version of chart: %s", err)
+						return
 					}
-				}
-				if skip {
-					continue
-				}
+					upgradeTemplate, err := c.RenderTemplate(templateOptions.ForUpgrade())
+					if err != nil {
+						t.Errorf("failed to render template as an upgrade: %s", err)
+						return
+					}
+					chart.CheckUpgrade(t, previousTemplate, upgradeTemplate)
+				})
+			}
+			strictDecoding := checker.NewStrictDecoding()
+			strictDecoding.AllowUnchecked = opts.StrictDecoding.AllowUnchecked
+			for _, check := range s.namedChecksFor(tc) {
 				if !opts.Coverage.Disabled {
-					if err := coverageTracker.Record(tc.TemplateOptions, check.Covers); err != nil {
+					if err := coverageTracker.Record(templateOptions, check.Covers); err != nil {
 						t.Errorf("failed to track coverage: %s", err)
 						// do not fail out, you should still continue with other checks
 					}
 				}
 				t.Run(check.Name, func(t *testing.T) {
-					template.Check(t, checker.NewCheckFunc(
+					template.Check(t, strictDecoding.NewCheckFunc(
 						append(beforeChecks, check.Checks...)...,
 					))
 				})
 			}
+			if opts.StrictDecoding.Enabled {
+				t.Run("StrictDecoding", func(t *testing.T) {
+					template.Check(t, strictDecoding.Check())
+				})
+			}
+		}
+		t.Run(tc.Name, func(t *testing.T) {
+			if len(kubeVersions) == 0 || tc.TemplateOptions.Capabilities != nil {
+				// cases that set their own capabilities are not run against each version of Kubernetes
+				runCase(t, tc.TemplateOptions, "")
+				return
+			}
+			for _, kubeVersion := range kubeVersions {
+				templateOptions, err := withKubeVersion(tc.TemplateOptions, kubeVersion)
+				if err != nil {
+					t.Error(err)
+					continue
+				}
+				t.Run(kubeVersion, func(t *testing.T) {
+					runCase(t, templateOptions, kubeVersion)
+				})
+			}
 		})
 	}
 	for _, tc := range s.FailureCases {
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"path/filepath"
 	"sort"
 	"strings"
 	"testing"
@@ -10,6 +11,7 @@ import (
 	"github.com/rancher/hull/pkg/utils"
 	"github.com/stretchr/testify/assert"
 	helmChartUtil "helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 const (
@@ -21,6 +23,17 @@ var (
 	chartPath        = utils.MustGetPathFromModuleRoot("testdata", "charts", "example-chart")
 	simpleChartPath  = utils.MustGetPathFromModuleRoot("testdata", "charts", "simple-chart")
 	badTemplatesPath = utils.MustGetPathFromModuleRoot("testdata", "charts", "bad-templates")
+
+	upgradeChartPath         = utils.MustGetPathFromModuleRoot("testdata", "charts", "upgrade-chart")
+	previousUpgradeChartPath = utils.MustGetPathFromModuleRoot("testdata", "charts", "upgrade-chart-previous")
+
+	schemaChartPath = utils.MustGetPathFromModuleRoot("testdata", "charts", "schema-chart")
+	crdsPath        = utils.MustGetPathFromModuleRoot("testdata", "crds")
+	crdChartPath    = utils.MustGetPathFromModuleRoot("testdata", "charts", "crd-chart")
+	crdChartCRDPath = utils.MustGetPathFromModuleRoot("testdata", "charts", "crd-chart-crd")
+
+	kubeVersionChartPath    = utils.MustGetPathFromModuleRoot("testdata", "charts", "kube-version-chart")
+	deprecatedAPIsChartPath = utils.MustGetPathFromModuleRoot("testdata", "charts", "deprecated-apis-chart")
 )
 
 // convert into jsonschema to validate values.schema.json contents
@@ -304,6 +317,350 @@ func TestRun(t *testing.T) {
 	})
 }
 
+func TestRunSnapshot(t *testing.T) {
+	suite := &Suite{
+		ChartPath: simpleChartPath,
+		Cases: []Case{
+			{
+				Name:            "Using Defaults",
+				TemplateOptions: chart.NewTemplateOptions(defaultReleaseName, defaultNamespace),
+			},
+			{
+				Name:            "Set Data",
+				TemplateOptions: chart.NewTemplateOptions(defaultReleaseName, defaultNamespace).SetValue("data.hello", "world"),
+			},
+		},
+	}
+	snapshotDir := t.TempDir()
+	opts := &SuiteOptions{
+		Coverage: CoverageOptions{
+			Disabled: true,
+		},
+		Snapshot: SnapshotOptions{
+			Enabled:   true,
+			Directory: snapshotDir,
+			Update:    true,
+		},
+	}
+
+	suite.Run(t, opts)
+	for _, c := range []string{"Using_Defaults", "Set_Data"} {
+		assert.FileExists(t, filepath.Join(snapshotDir, "TestRunSnapshot", c, "templates", "configmap.yaml"))
+	}
+
+	// compare against the snapshot that was just written
+	opts.Snapshot.Update = false
+	suite.Run(t, opts)
+}
+
+func TestRunUpgrade(t *testing.T) {
+	testCases := []struct {
+		Name             string
+		Cases            []Case
+		UpgradeOptions   UpgradeOptions
+		ShouldThrowError bool
+	}{
+		{
+			Name: "Previous Chart Path",
+			Cases: []Case{
+				{
+					Name:            "Using Defaults",
+					TemplateOptions: chart.NewTemplateOptions("upgrade-chart", defaultNamespace),
+				},
+			},
+			UpgradeOptions: UpgradeOptions{
+				Enabled:           true,
+				PreviousChartPath: previousUpgradeChartPath,
+			},
+		},
+		{
+			Name: "Index Path",
+			Cases: []Case{
+				{
+					Name:            "Using Defaults",
+					TemplateOptions: chart.NewTemplateOptions("upgrade-chart", defaultNamespace),
+				},
+			},
+			UpgradeOptions: UpgradeOptions{
+				Enabled:   true,
+				IndexPath: "testdata/index.yaml",
+			},
+		},
+		{
+			Name: "No Previous Chart",
+			UpgradeOptions: UpgradeOptions{
+				Enabled: true,
+			},
+			ShouldThrowError: true,
+		},
+		{
+			Name: "Chart Not In Index",
+			UpgradeOptions: UpgradeOptions{
+				Enabled:   true,
+				IndexPath: "testdata/does-not-exist.yaml",
+			},
+			ShouldThrowError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			suite := &Suite{
+				ChartPath: upgradeChartPath,
+				Cases:     tc.Cases,
+			}
+			opts := &SuiteOptions{
+				Coverage: CoverageOptions{
+					Disabled: true,
+				},
+				Upgrade: tc.UpgradeOptions,
+			}
+			if !tc.ShouldThrowError {
+				suite.Run(t, opts)
+				return
+			}
+			fakeT := &testing.T{}
+			suite.Run(fakeT, opts)
+			assert.True(t, fakeT.Failed(), "expected error to be thrown")
+		})
+	}
+}
+
+func TestRunSchemaValidation(t *testing.T) {
+	suite := &Suite{
+		ChartPath: schemaChartPath,
+		Cases: []Case{
+			{
+				Name:            "Using Defaults",
+				TemplateOptions: chart.NewTemplateOptions(defaultReleaseName, defaultNamespace),
+			},
+			{
+				Name:            "Kubernetes 1.27",
+				TemplateOptions: chart.NewTemplateOptions(defaultReleaseName, defaultNamespace).SetKubeVersion("v1.27.0"),
+			},
+			{
+				Name:            "Set Widget",
+				TemplateOptions: chart.NewTemplateOptions(defaultReleaseName, defaultNamespace).SetValue("widget.size", "large").SetValue("widget.port", "8080"),
+			},
+		},
+	}
+	opts := &SuiteOptions{
+		Coverage: CoverageOptions{
+			Disabled: true,
+		},
+		SchemaValidation: SchemaValidationOptions{
+			Enabled:  true,
+			CRDPaths: []string{crdsPath},
+		},
+	}
+	suite.Run(t, opts)
+
+	t.Run("CRD Chart", func(t *testing.T) {
+		suite := &Suite{
+			ChartPath: crdChartPath,
+			Cases: []Case{
+				{
+					Name:            "Using Defaults",
+					TemplateOptions: chart.NewTemplateOptions(defaultReleaseName, defaultNamespace),
+				},
+				{
+					Name:            "Manual Gadget",
+					TemplateOptions: chart.NewTemplateOptions(defaultReleaseName, defaultNamespace).SetValue("gadget.mode", "manual").SetValue("widget.size", "medium"),
+				},
+			},
+		}
+		opts := &SuiteOptions{
+			Coverage: CoverageOptions{
+				Disabled: true,
+			},
+			SchemaValidation: SchemaValidationOptions{
+				Enabled:       true,
+				CRDChartPaths: []string{crdChartCRDPath},
+			},
+		}
+		suite.Run(t, opts)
+	})
+}
+
+func TestRunKubeVersions(t *testing.T) {
+	var renderedKubeVersions []string
+	suite := &Suite{
+		ChartPath: kubeVersionChartPath,
+		Cases: []Case{
+			{
+				Name:            "Using Defaults",
+				TemplateOptions: chart.NewTemplateOptions(defaultReleaseName, defaultNamespace),
+			},
+			{
+				Name:            "Kubernetes 1.16",
+				TemplateOptions: chart.NewTemplateOptions(defaultReleaseName, defaultNamespace).SetKubeVersion("v1.16.0"),
+			},
+		},
+		NamedChecks: []NamedCheck{
+			{
+				Name: "PodSecurityPolicy Is Only Served Before 1.25",
+				Checks: Checks{
+					checker.Once(func(tc *checker.TestContext) {
+						capabilities := tc.RenderValues["Capabilities"].(*helmChartUtil.Capabilities)
+						renderedKubeVersions = append(renderedKubeVersions, capabilities.KubeVersion.Version)
+						servesPSPs := capabilities.APIVersions.Has("policy/v1beta1/PodSecurityPolicy")
+						if capabilities.KubeVersion.Minor == "24" {
+							assert.True(tc.T, servesPSPs, "expected PodSecurityPolicy to be served by %s", capabilities.KubeVersion)
+						} else {
+							assert.False(tc.T, servesPSPs, "expected PodSecurityPolicy to not be served by %s", capabilities.KubeVersion)
+						}
+					}),
+				},
+			},
+		},
+	}
+	opts := &SuiteOptions{
+		Coverage: CoverageOptions{
+			Disabled: true,
+		},
+		KubeVersions: KubeVersionOptions{
+			Enabled: true,
+		},
+	}
+	suite.Run(t, opts)
+	assert.Equal(t, []string{"v1.24.0", "v1.25.0", "v1.16.0"}, renderedKubeVersions)
+}
+
+func TestRunDeprecatedAPIs(t *testing.T) {
+	suite := &Suite{
+		ChartPath: deprecatedAPIsChartPath,
+		Cases: []Case{
+			{
+				Name:            "Using Defaults",
+				TemplateOptions: chart.NewTemplateOptions(defaultReleaseName, defaultNamespace).SetKubeVersion("v1.21.0"),
+			},
+			{
+				Name:            "Deprecated PodDisruptionBudget",
+				TemplateOptions: chart.NewTemplateOptions(defaultReleaseName, defaultNamespace).WithKubeRelease("1.24").SetValue("pdbAPIVersion", "policy/v1beta1"),
+			},
+		},
+	}
+	opts := &SuiteOptions{
+		Coverage: CoverageOptions{
+			Disabled: true,
+		},
+		DeprecatedAPIs: DeprecatedAPIOptions{
+			Enabled: true,
+		},
+	}
+	suite.Run(t, opts)
+}
+
+func TestRunStrictDecoding(t *testing.T) {
+	suite := &Suite{
+		ChartPath: simpleChartPath,
+		Cases: []Case{
+			{
+				Name:            "Using Defaults",
+				TemplateOptions: chart.NewTemplateOptions(defaultReleaseName, defaultNamespace),
+			},
+		},
+		NamedChecks: []NamedCheck{
+			{
+				Name: "All Objects Have Names",
+				Checks: Checks{
+					checker.NewChainedCheckFunc(func(tc *checker.TestContext, objs struct{ Unstructured []*unstructured.Unstructured }) {
+						for _, obj := range objs.Unstructured {
+							assert.NotEmpty(tc.T, obj.GetName())
+						}
+					}),
+				},
+			},
+		},
+	}
+	opts := &SuiteOptions{
+		Coverage: CoverageOptions{
+			Disabled: true,
+		},
+		StrictDecoding: StrictDecodingOptions{
+			Enabled: true,
+		},
+	}
+	suite.Run(t, opts)
+}
+
+func TestStrictDecodingLostFields(t *testing.T) {
+	c, err := chart.NewChart(chartPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template, err := c.RenderTemplate(chart.NewTemplateOptions(defaultReleaseName, defaultNamespace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	strictDecoding := checker.NewStrictDecoding()
+	strictDecoding.AllowUnchecked = true
+	// the RoleBinding of the example-chart sets roleRef.namespace, which is not a field of a RoleRef
+	fakeT := &testing.T{}
+	template.Check(fakeT, strictDecoding.Check())
+	assert.True(t, fakeT.Failed())
+}
+
+func TestGetKubeVersions(t *testing.T) {
+	testCases := []struct {
+		Name             string
+		ChartPath        string
+		Versions         []string
+		ShouldThrowError bool
+		ExpectVersions   []string
+	}{
+		{
+			Name:           "Versions Provided",
+			ChartPath:      schemaChartPath,
+			Versions:       []string{"v1.20.0", "v1.28.3"},
+			ExpectVersions: []string{"v1.20.0", "v1.28.3"},
+		},
+		{
+			Name:             "Invalid Version Provided",
+			ChartPath:        schemaChartPath,
+			Versions:         []string{"not-a-version"},
+			ShouldThrowError: true,
+		},
+		{
+			Name:           "From Chart kubeVersion",
+			ChartPath:      kubeVersionChartPath,
+			ExpectVersions: []string{"v1.24.0", "v1.25.0"},
+		},
+		{
+			Name:           "From Rancher Annotation",
+			ChartPath:      chartPath,
+			ExpectVersions: chart.SupportedKubeVersions(),
+		},
+		{
+			Name:             "Invalid Constraint",
+			ChartPath:        utils.MustGetPathFromModuleRoot("testdata", "charts", "invalid-kube-constraint"),
+			ShouldThrowError: true,
+		},
+		{
+			Name:             "No Constraint",
+			ChartPath:        schemaChartPath,
+			ShouldThrowError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			c, err := chart.NewChart(tc.ChartPath)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			kubeVersions, err := KubeVersionOptions{Enabled: true, Versions: tc.Versions}.getKubeVersions(c)
+			if tc.ShouldThrowError {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.ExpectVersions, kubeVersions)
+		})
+	}
+}
+
 func TestGetRancherOptions(t *testing.T) {
 	o := GetRancherOptions()
 	assert.NotNil(t, o, "RancherOptions should not be nil")
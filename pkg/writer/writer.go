@@ -31,6 +31,13 @@ type outputWriter struct {
 	Command string
 	Raw     string
 
+	// OutputType overrides the markdown type used to render the output, which defaults to the type of the Source
+	OutputType string
+
+	// Location is the location in the templates of a chart that the output is reported at, i.e.
+	// templates/deployment.yaml:12-20
+	Location string
+
 	outputFs billy.Filesystem
 }
 
@@ -52,6 +59,21 @@ func NewOutputWriter(t *testing.T, source, command, raw string) io.Writer {
 	return w
 }
 
+// NewDiffWriter returns an output writer whose output is rendered as a diff against the raw contents of the source
+func NewDiffWriter(t *testing.T, source, command, raw string) io.Writer {
+	w := NewOutputWriter(t, source, command, raw).(*outputWriter)
+	w.OutputType = "diff"
+	return w
+}
+
+// NewFailureWriter returns an output writer whose output is a failure reported at the location in the templates of a
+// chart, i.e. templates/deployment.yaml:12-20
+func NewFailureWriter(t *testing.T, location string) io.Writer {
+	w := NewOutputWriter(t, "", "", "").(*outputWriter)
+	w.Location = location
+	return w
+}
+
 func (w *outputWriter) SetOutputDir(outputDir string) {
 	if outputDir == "" {
 		return
@@ -87,6 +109,11 @@ func (w *outputWriter) Write(out []byte) (n int, err error) {
 		markdownType = "json"
 	}
 
+	outputType := markdownType
+	if len(w.OutputType) > 0 {
+		outputType = w.OutputType
+	}
+
 	_, err = f.Write([]byte(fmt.Sprintf("## %s\n", w.Name)))
 
 	if len(w.Raw) > 0 && len(w.Source) > 0 {
@@ -123,7 +150,31 @@ func (w *outputWriter) Write(out []byte) (n int, err error) {
 		if err != nil {
 			return 0, err
 		}
-		_, err = f.Write([]byte(fmt.Sprintf("```%s\n", markdownType)))
+		_, err = f.Write([]byte(fmt.Sprintf("```%s\n", outputType)))
+		if err != nil {
+			return 0, err
+		}
+		_, err = f.Write(out)
+		if err != nil {
+			return 0, err
+		}
+		_, err = f.Write([]byte("\n```\n"))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if len(w.Location) > 0 && len(out) > 0 {
+		_, err = f.Write([]byte("\n### Failure\n\n"))
+		if err != nil {
+			return 0, err
+		}
+		locationString := fmt.Sprintf("**Source:** `%s`\n\n", w.Location)
+		_, err = f.Write([]byte(locationString))
+		if err != nil {
+			return 0, err
+		}
+		_, err = f.Write([]byte("```\n"))
 		if err != nil {
 			return 0, err
 		}
@@ -23,6 +23,9 @@ var rawFmt string
 //go:embed formats/output_format.md
 var outputFmt string
 
+//go:embed formats/failure_format.md
+var failureFmt string
+
 func TestGetOutputFsFromEnv(t *testing.T) {
 	wd, err := os.Getwd()
 	if err != nil {
@@ -104,6 +107,10 @@ func TestOutputWriter(t *testing.T) {
 
 		Command string
 		Out     string
+
+		Diff bool
+
+		Location string
 	}{
 		{
 			Name: "No Values",
@@ -158,13 +165,38 @@ func TestOutputWriter(t *testing.T) {
 
 			Out: "hello-world",
 		},
+		{
+			Name: "Diff",
+
+			Source: "templates/mytemplate.yaml",
+			Raw:    "hello-world",
+
+			Command: "helm template",
+			Out:     "-hello-world\n+hello-rancher",
+
+			Diff: true,
+		},
+		{
+			Name: "Failure",
+
+			Out: "Deployment {default hello}: unexpected value for .spec.replicas",
+
+			Location: "templates/deployment.yaml:12-20",
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.Name, func(t *testing.T) {
 			outputFs := memfs.New()
 
-			w := NewOutputWriter(t, tc.Source, tc.Command, tc.Raw)
+			newWriter := NewOutputWriter
+			if tc.Diff {
+				newWriter = NewDiffWriter
+			}
+			w := newWriter(t, tc.Source, tc.Command, tc.Raw)
+			if len(tc.Location) > 0 {
+				w = NewFailureWriter(t, tc.Location)
+			}
 			cpw := w.(*outputWriter)
 			cpw.outputFs = outputFs
 
@@ -208,8 +240,14 @@ func TestOutputWriter(t *testing.T) {
 				expectedOutput += "\n" + fmt.Sprintf(rawFmt, tc.Source, ext, tc.Raw)
 			}
 			if len(tc.Command) > 0 && len(tc.Out) > 0 {
-
-				expectedOutput += "\n" + fmt.Sprintf(outputFmt, tc.Command, ext, tc.Out)
+				outExt := ext
+				if tc.Diff {
+					outExt = "diff"
+				}
+				expectedOutput += "\n" + fmt.Sprintf(outputFmt, tc.Command, outExt, tc.Out)
+			}
+			if len(tc.Location) > 0 && len(tc.Out) > 0 {
+				expectedOutput += "\n" + fmt.Sprintf(failureFmt, tc.Location, tc.Out)
 			}
 			assert.Equal(t, expectedOutput, string(outputFileContents))
 		})